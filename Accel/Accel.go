@@ -0,0 +1,66 @@
+package Accel
+
+import (
+	"goRay/Object"
+	"goRay/Ray"
+	"math"
+)
+
+// Structure answers ray queries against a fixed set of objects
+type Structure interface {
+	// Closest returns the nearest object the ray hits and the distance to it
+	Closest(ray Ray.Ray) (Object.Object, float64, bool)
+	// Occluded reports whether anything blocks the ray before maxDistance
+	Occluded(ray Ray.Ray, maxDistance float64) bool
+}
+
+// Linear tests every object for every ray, it is the reference the BVH is checked against
+type Linear struct {
+	objects []Object.Object
+}
+
+func NewLinear(objects []Object.Object) *Linear {
+	return &Linear{objects: objects}
+}
+
+func (l *Linear) Closest(ray Ray.Ray) (Object.Object, float64, bool) {
+	return closestOf(l.objects, ray, math.MaxFloat64)
+}
+
+func (l *Linear) Occluded(ray Ray.Ray, maxDistance float64) bool {
+	return anyOf(l.objects, ray, maxDistance)
+}
+
+func closestOf(objects []Object.Object, ray Ray.Ray, maxDistance float64) (Object.Object, float64, bool) {
+	var closest Object.Object
+	closestT := maxDistance
+	for _, object := range objects {
+		if intersects, t := object.IntersectDistance(ray); intersects && t < closestT {
+			closest = object
+			closestT = t
+		}
+	}
+	return closest, closestT, closest != nil
+}
+
+func anyOf(objects []Object.Object, ray Ray.Ray, maxDistance float64) bool {
+	for _, object := range objects {
+		if intersects, t := object.IntersectDistance(ray); intersects && t < maxDistance {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten expands aggregates into their primitives
+func flatten(objects []Object.Object) []Object.Object {
+	var flat []Object.Object
+	for _, object := range objects {
+		if aggregate, ok := object.(Object.Aggregate); ok {
+			flat = append(flat, flatten(aggregate.Primitives())...)
+			continue
+		}
+		flat = append(flat, object)
+	}
+	return flat
+}
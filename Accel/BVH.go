@@ -0,0 +1,340 @@
+package Accel
+
+import (
+	"goRay/Object"
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+	"sort"
+)
+
+const (
+	// sahBins is the number of buckets centroids are sorted into when looking for a split
+	sahBins = 12
+	// maxLeafSize caps leaves even when the surface area heuristic says splitting isn't worth it
+	maxLeafSize = 8
+	// traversalCost is the cost of visiting a node relative to one intersection test
+	traversalCost = 1.0
+)
+
+// BVH is a bounding volume hierarchy built with the surface area heuristic.
+// Objects with infinite bounds, such as planes, are kept aside and tested linearly.
+type BVH struct {
+	nodes     []node
+	objects   []Object.Object
+	unbounded []Object.Object
+}
+
+// node is a leaf when count > 0, its objects are objects[first:first+count].
+// Inner nodes store their second child at index second, the first child follows
+// the node directly.
+type node struct {
+	bounds Object.Bounds
+	first  int
+	count  int
+	second int
+	axis   int
+}
+
+type primitive struct {
+	object   Object.Object
+	bounds   Object.Bounds
+	centroid [3]float64
+}
+
+func NewBVH(objects []Object.Object) *BVH {
+	bvh := &BVH{}
+
+	var primitives []primitive
+	for _, object := range flatten(objects) {
+		bounds := object.GetBounds()
+		if !bounds.IsFinite() {
+			bvh.unbounded = append(bvh.unbounded, object)
+			continue
+		}
+		centroid := bounds.Centroid()
+		primitives = append(primitives, primitive{
+			object:   object,
+			bounds:   bounds,
+			centroid: [3]float64{centroid.X(), centroid.Y(), centroid.Z()},
+		})
+	}
+
+	if len(primitives) > 0 {
+		bvh.nodes = make([]node, 0, 2*len(primitives))
+		bvh.build(primitives, 0, len(primitives))
+		bvh.objects = make([]Object.Object, len(primitives))
+		for i, p := range primitives {
+			bvh.objects[i] = p.object
+		}
+	}
+	return bvh
+}
+
+// build appends the subtree for primitives[start:end] and returns its node index.
+// primitives is reordered in place so that every leaf covers a contiguous range.
+func (b *BVH) build(primitives []primitive, start, end int) int {
+	bounds := Object.EmptyBounds()
+	centroidBounds := Object.EmptyBounds()
+	for _, p := range primitives[start:end] {
+		bounds = bounds.Union(p.bounds)
+		centroidBounds = centroidBounds.Grow(p.bounds.Centroid())
+	}
+
+	index := len(b.nodes)
+	b.nodes = append(b.nodes, node{bounds: bounds, first: start, count: end - start})
+
+	count := end - start
+	if count <= 2 {
+		return index
+	}
+
+	axis, split, ok := findSplit(primitives[start:end], bounds, centroidBounds)
+	if !ok {
+		if count <= maxLeafSize {
+			return index
+		}
+		// no useful split, fall back to halving along the widest axis
+		axis = widestAxis(centroidBounds)
+		sort.Slice(primitives[start:end], func(i, j int) bool {
+			return primitives[start+i].centroid[axis] < primitives[start+j].centroid[axis]
+		})
+		split = count / 2
+	} else {
+		split = partition(primitives[start:end], axis, split, centroidBounds)
+	}
+
+	b.nodes[index].count = 0
+	b.nodes[index].axis = axis
+	b.build(primitives, start, start+split)
+	second := b.build(primitives, start+split, end)
+	b.nodes[index].second = second
+	return index
+}
+
+// findSplit returns the axis and bucket to split at, or false when a leaf is cheaper
+func findSplit(primitives []primitive, bounds, centroidBounds Object.Bounds) (int, int, bool) {
+	parentArea := bounds.SurfaceArea()
+	leafCost := float64(len(primitives))
+
+	bestCost := math.Inf(1)
+	bestAxis, bestBucket := -1, 0
+	for axis := 0; axis < 3; axis++ {
+		low, high := component(centroidBounds.Min, axis), component(centroidBounds.Max, axis)
+		if high <= low {
+			continue
+		}
+
+		var buckets [sahBins]struct {
+			count  int
+			bounds Object.Bounds
+		}
+		for i := range buckets {
+			buckets[i].bounds = Object.EmptyBounds()
+		}
+		for _, p := range primitives {
+			bucket := bucketOf(p.centroid[axis], low, high)
+			buckets[bucket].count++
+			buckets[bucket].bounds = buckets[bucket].bounds.Union(p.bounds)
+		}
+
+		// sweep from the right so every split's right side is known in one pass
+		var rightCounts [sahBins]int
+		var rightAreas [sahBins]float64
+		right := Object.EmptyBounds()
+		rightCount := 0
+		for i := sahBins - 1; i > 0; i-- {
+			right = right.Union(buckets[i].bounds)
+			rightCount += buckets[i].count
+			rightCounts[i] = rightCount
+			rightAreas[i] = right.SurfaceArea()
+		}
+
+		left := Object.EmptyBounds()
+		leftCount := 0
+		for i := 1; i < sahBins; i++ {
+			left = left.Union(buckets[i-1].bounds)
+			leftCount += buckets[i-1].count
+			if leftCount == 0 || rightCounts[i] == 0 {
+				continue
+			}
+			cost := traversalCost + (float64(leftCount)*left.SurfaceArea()+float64(rightCounts[i])*rightAreas[i])/parentArea
+			if cost < bestCost {
+				bestCost = cost
+				bestAxis = axis
+				bestBucket = i
+			}
+		}
+	}
+
+	if bestAxis < 0 || (bestCost >= leafCost && len(primitives) <= maxLeafSize) {
+		return 0, 0, false
+	}
+	return bestAxis, bestBucket, true
+}
+
+// partition moves primitives in buckets below split to the front and returns how many there are
+func partition(primitives []primitive, axis, split int, centroidBounds Object.Bounds) int {
+	low, high := component(centroidBounds.Min, axis), component(centroidBounds.Max, axis)
+	i := 0
+	for j := range primitives {
+		if bucketOf(primitives[j].centroid[axis], low, high) < split {
+			primitives[i], primitives[j] = primitives[j], primitives[i]
+			i++
+		}
+	}
+	return i
+}
+
+func bucketOf(value, low, high float64) int {
+	bucket := int(sahBins * (value - low) / (high - low))
+	if bucket >= sahBins {
+		bucket = sahBins - 1
+	}
+	if bucket < 0 {
+		bucket = 0
+	}
+	return bucket
+}
+
+func widestAxis(bounds Object.Bounds) int {
+	axis := 0
+	widest := -1.0
+	for i := 0; i < 3; i++ {
+		if extent := component(bounds.Max, i) - component(bounds.Min, i); extent > widest {
+			widest = extent
+			axis = i
+		}
+	}
+	return axis
+}
+
+// traversal holds the per ray values reused at every node
+type traversal struct {
+	origin   [3]float64
+	inverse  [3]float64
+	negative [3]bool
+	stack    []int
+}
+
+func newTraversal(ray Ray.Ray) traversal {
+	origin := ray.Origin()
+	direction := ray.Direction()
+	t := traversal{
+		origin: [3]float64{origin.X(), origin.Y(), origin.Z()},
+		stack:  make([]int, 0, 64),
+	}
+	for axis, d := range [3]float64{direction.X(), direction.Y(), direction.Z()} {
+		t.inverse[axis] = 1 / d
+		// checking the inverse rather than d sorts -0 to the negative side, matching its -Inf
+		t.negative[axis] = t.inverse[axis] < 0
+	}
+	return t
+}
+
+// hitsBounds is the slab test, true when the box is entered before maxDistance
+func (t *traversal) hitsBounds(bounds Object.Bounds, maxDistance float64) bool {
+	tNear, tFar := 0.0, maxDistance
+	for axis := 0; axis < 3; axis++ {
+		low, high := component(bounds.Min, axis), component(bounds.Max, axis)
+		t0 := (low - t.origin[axis]) * t.inverse[axis]
+		t1 := (high - t.origin[axis]) * t.inverse[axis]
+		if t.negative[axis] {
+			t0, t1 = t1, t0
+		}
+		// NaN comes from 0 * Inf when the origin lies on a slab of a parallel ray, treat it as inside
+		if t0 > tNear {
+			tNear = t0
+		}
+		if t1 < tFar {
+			tFar = t1
+		}
+		if tNear > tFar {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BVH) Closest(ray Ray.Ray) (Object.Object, float64, bool) {
+	closest, closestT, _ := closestOf(b.unbounded, ray, math.MaxFloat64)
+	if len(b.nodes) == 0 {
+		return closest, closestT, closest != nil
+	}
+
+	t := newTraversal(ray)
+	t.push(0)
+	for len(t.stack) > 0 {
+		index := t.pop()
+		current := &b.nodes[index]
+		if !t.hitsBounds(current.bounds, closestT) {
+			continue
+		}
+
+		if current.count > 0 {
+			if object, objectT, ok := closestOf(b.objects[current.first:current.first+current.count], ray, closestT); ok {
+				closest, closestT = object, objectT
+			}
+			continue
+		}
+
+		// visit the child on the near side of the split first so far boxes get culled
+		if t.negative[current.axis] {
+			t.push(index + 1)
+			t.push(current.second)
+		} else {
+			t.push(current.second)
+			t.push(index + 1)
+		}
+	}
+	return closest, closestT, closest != nil
+}
+
+func (b *BVH) Occluded(ray Ray.Ray, maxDistance float64) bool {
+	if anyOf(b.unbounded, ray, maxDistance) {
+		return true
+	}
+	if len(b.nodes) == 0 {
+		return false
+	}
+
+	t := newTraversal(ray)
+	t.push(0)
+	for len(t.stack) > 0 {
+		index := t.pop()
+		current := &b.nodes[index]
+		if !t.hitsBounds(current.bounds, maxDistance) {
+			continue
+		}
+
+		if current.count > 0 {
+			if anyOf(b.objects[current.first:current.first+current.count], ray, maxDistance) {
+				return true
+			}
+			continue
+		}
+		t.push(current.second)
+		t.push(index + 1)
+	}
+	return false
+}
+
+func (t *traversal) push(index int) {
+	t.stack = append(t.stack, index)
+}
+
+func (t *traversal) pop() int {
+	index := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	return index
+}
+
+func component(v Vector.Vector, axis int) float64 {
+	switch axis {
+	case 0:
+		return v.X()
+	case 1:
+		return v.Y()
+	}
+	return v.Z()
+}
@@ -0,0 +1,190 @@
+package Accel
+
+import (
+	"goRay/Object"
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var white = *Vector.New(1, 1, 1)
+
+func TestBVHMatchesLinear(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	objects := randomScene(rng, 2000)
+	objects = append(objects, Object.NewPlane(*Vector.New(0, 600, 0), *Vector.New(0, -1, 0), white))
+
+	bvh := NewBVH(objects)
+	linear := NewLinear(flatten(objects))
+
+	for i := 0; i < 2000; i++ {
+		ray := randomRay(rng)
+
+		bvhObject, bvhT, bvhHit := bvh.Closest(ray)
+		linearObject, linearT, linearHit := linear.Closest(ray)
+		if bvhHit != linearHit || bvhObject != linearObject || bvhT != linearT {
+			t.Fatalf("Ray %d: BVH found %v at %g, linear found %v at %g", i, bvhObject, bvhT, linearObject, linearT)
+		}
+
+		maxDistance := rng.Float64() * 1000
+		if bvh.Occluded(ray, maxDistance) != linear.Occluded(ray, maxDistance) {
+			t.Fatalf("Ray %d: BVH and linear disagree on occlusion within %g", i, maxDistance)
+		}
+	}
+}
+
+func TestBVHIndexesMeshTriangles(t *testing.T) {
+	near := Object.NewTriangle(*Vector.New(-1, -1, 10), *Vector.New(0, 1, 10), *Vector.New(1, -1, 10), white)
+	far := Object.NewTriangle(*Vector.New(-1, -1, 20), *Vector.New(0, 1, 20), *Vector.New(1, -1, 20), white)
+	mesh := Object.NewMesh([]*Object.Triangle{far, near}, white)
+
+	bvh := NewBVH([]Object.Object{mesh})
+	object, distance, ok := bvh.Closest(Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1)))
+	if !ok || distance != 10 {
+		t.Fatalf("Expected to hit the mesh at 10, got %t %g", ok, distance)
+	}
+	if object != Object.Object(near) {
+		t.Errorf("Expected the near triangle to be returned, got %v", object)
+	}
+}
+
+func TestBVHOccluded(t *testing.T) {
+	bvh := NewBVH([]Object.Object{
+		Object.NewSphere(*Vector.New(0, 0, 50), white, 5),
+		Object.NewSphere(*Vector.New(30, 0, 50), white, 5),
+		Object.NewSphere(*Vector.New(-30, 0, 50), white, 5),
+	})
+
+	tests := []struct {
+		ray         Ray.Ray
+		maxDistance float64
+		occluded    bool
+	}{
+		{
+			ray:         Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1)),
+			maxDistance: 100,
+			occluded:    true,
+		},
+		{
+			ray:         Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1)),
+			maxDistance: 40,
+			occluded:    false,
+		},
+		{
+			ray:         Ray.New(Vector.Vector{}, *Vector.New(0, 1, 0)),
+			maxDistance: math.Inf(1),
+			occluded:    false,
+		},
+		{
+			ray:         Ray.New(*Vector.New(-100, 0, 50), *Vector.New(1, 0, 0)),
+			maxDistance: 200,
+			occluded:    true,
+		},
+		{
+			ray:         Ray.New(*Vector.New(100, 0, 50), Vector.New(1, 0, 0).Reverse()),
+			maxDistance: 200,
+			occluded:    true,
+		},
+	}
+
+	for i, tt := range tests {
+		if occluded := bvh.Occluded(tt.ray, tt.maxDistance); occluded != tt.occluded {
+			t.Errorf("Test %d: Expected occluded to be '%t', got '%t'", i, tt.occluded, occluded)
+		}
+	}
+}
+
+func TestEmptyBVH(t *testing.T) {
+	bvh := NewBVH(nil)
+	ray := Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1))
+	if _, _, ok := bvh.Closest(ray); ok {
+		t.Errorf("Expected an empty BVH to miss")
+	}
+	if bvh.Occluded(ray, math.Inf(1)) {
+		t.Errorf("Expected an empty BVH not to occlude")
+	}
+}
+
+func BenchmarkBVH_Closest10k(b *testing.B) {
+	benchmarkClosest(b, func(objects []Object.Object) Structure { return NewBVH(objects) })
+}
+
+func BenchmarkLinear_Closest10k(b *testing.B) {
+	benchmarkClosest(b, func(objects []Object.Object) Structure { return NewLinear(objects) })
+}
+
+func BenchmarkBVH_Occluded10k(b *testing.B) {
+	benchmarkOccluded(b, func(objects []Object.Object) Structure { return NewBVH(objects) })
+}
+
+func BenchmarkLinear_Occluded10k(b *testing.B) {
+	benchmarkOccluded(b, func(objects []Object.Object) Structure { return NewLinear(objects) })
+}
+
+func BenchmarkBVH_Build10k(b *testing.B) {
+	objects := randomScene(rand.New(rand.NewSource(1)), 10000)
+	for i := 0; i < b.N; i++ {
+		NewBVH(objects)
+	}
+}
+
+func benchmarkClosest(b *testing.B, build func([]Object.Object) Structure) {
+	rng := rand.New(rand.NewSource(1))
+	structure := build(randomScene(rng, 10000))
+	rays := randomRays(rng, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		structure.Closest(rays[i%len(rays)])
+	}
+}
+
+func benchmarkOccluded(b *testing.B, build func([]Object.Object) Structure) {
+	rng := rand.New(rand.NewSource(1))
+	structure := build(randomScene(rng, 10000))
+	rays := randomRays(rng, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		structure.Occluded(rays[i%len(rays)], 500)
+	}
+}
+
+// randomScene scatters a mix of small spheres and triangles through a 1000 unit cube
+func randomScene(rng *rand.Rand, n int) []Object.Object {
+	objects := make([]Object.Object, n)
+	for i := range objects {
+		center := randomPoint(rng, 500)
+		if i%2 == 0 {
+			objects[i] = Object.NewSphere(center, white, 1+rng.Intn(5))
+			continue
+		}
+		objects[i] = Object.NewTriangle(
+			center.Translate(randomPoint(rng, 5)),
+			center.Translate(randomPoint(rng, 5)),
+			center.Translate(randomPoint(rng, 5)),
+			white,
+		)
+	}
+	return objects
+}
+
+func randomRays(rng *rand.Rand, n int) []Ray.Ray {
+	rays := make([]Ray.Ray, n)
+	for i := range rays {
+		rays[i] = randomRay(rng)
+	}
+	return rays
+}
+
+func randomRay(rng *rand.Rand) Ray.Ray {
+	return Ray.New(randomPoint(rng, 600), randomPoint(rng, 1).Normalize())
+}
+
+func randomPoint(rng *rand.Rand, extent float64) Vector.Vector {
+	return *Vector.New(
+		(rng.Float64()*2-1)*extent,
+		(rng.Float64()*2-1)*extent,
+		(rng.Float64()*2-1)*extent,
+	)
+}
@@ -0,0 +1,22 @@
+package Camera
+
+import (
+	"goRay/Vector"
+)
+
+// Background is the light arriving along rays that leave the scene without hitting
+// anything. A background that is also a Light.Light, such as an environment map,
+// lights the scene too.
+type Background interface {
+	Radiance(direction Vector.Vector) Vector.Vector
+}
+
+// Sky is the default background, white towards the horizon and blue overhead
+type Sky struct{}
+
+func (Sky) Radiance(direction Vector.Vector) Vector.Vector {
+	t := direction.Y()*0.5 + 1
+	blue := Vector.New(0.5, 0.7, 1.0)
+	white := Vector.New(1, 1, 1)
+	return white.Scale(1 - t).Translate(blue.Scale(t))
+}
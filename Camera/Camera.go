@@ -1,13 +1,32 @@
 package Camera
 
 import (
+	"context"
+	"goRay/Accel"
+	"goRay/Film"
+	"goRay/Light"
+	"goRay/Material"
 	"goRay/Object"
 	"goRay/Ray"
+	"goRay/Sampler"
 	"goRay/Vector"
 	"image/color"
 	"math"
-	"math/rand"
-	"sync"
+)
+
+// shadowBias moves shadow and reflected ray origins off the surface so they don't hit it again
+const shadowBias = 1e-4
+
+// defaultMaxDepth is how many times a ray may bounce between mirrors
+const defaultMaxDepth = 5
+
+// defaultShadowSamples is how many shadow rays go towards each area light per hit
+const defaultShadowSamples = 16
+
+// sampler dimensions used for the position within the pixel and on the lens
+const (
+	pixelDimension = 0
+	lensDimension  = 1
 )
 
 type PixelGrabber interface {
@@ -19,31 +38,39 @@ type Camera struct {
 	width                     int
 	origin                    Vector.Vector
 	ObjectList                []Object.Object
+	Lights                    []Light.Light
 	pixelList                 []Pixel
 	ScreenCellMatrix          [][]*Vector.Vector
 	YRotation                 float64
+	Pitch                     float64
+	Roll                      float64
 	cameraRotationTransformer func(Vector.Vector) Vector.Vector
 	CameraPosition            Vector.Vector
 	primaryRays               []Ray.Ray
 	antiAliasingFactor        int
+	maxDepth                  int
+	shadowSamples             int
+	projection                Projection
+	sampler                   Sampler.Sampler
+	integrator                Integrator
+	background                Background
+	// backgroundLight is the background when it also lights the scene
+	backgroundLight Light.Light
+	// emitters are the lights given off by emissive objects, found with the structure
+	emitters []Light.Light
+	// apertureRadius of the thin lens, 0 is a pinhole with everything in focus
+	apertureRadius float64
+	focalDistance  float64
+	// structure indexes ObjectList, it is rebuilt before casting after objects change
+	structure        Accel.Structure
+	progressObserver ProgressObserver
+	// rays counts the rays traced by all casts, it is only accessed atomically
+	rays int64
 }
 
 func New(width int, height int, origin Vector.Vector) *Camera {
-
-	heightMatrix := getScreenMatrix(float64(height))
-	widthMatrix := getScreenMatrix(float64(width))
-
-	screenCellMatrix := make([][]*Vector.Vector, height)
-	for row, y := range heightMatrix {
-		screenCellMatrix[row] = make([]*Vector.Vector, width)
-		for col, x := range widthMatrix {
-			screenCellMatrix[row][col] = GetPixelHeadingVector(y, x, float64(height))
-		}
-	}
-
-	return &Camera{
-		ScreenCellMatrix:          screenCellMatrix,
-		cameraRotationTransformer: Vector.RotateYBuilder(0),
+	c := &Camera{
+		cameraRotationTransformer: Vector.RotateBuilder(0, 0, 0),
 		height:                    height,
 		width:                     width,
 		origin:                    origin,
@@ -51,7 +78,81 @@ func New(width int, height int, origin Vector.Vector) *Camera {
 		YRotation:                 0,
 		CameraPosition:            Vector.Vector{},
 		antiAliasingFactor:        0,
+		maxDepth:                  defaultMaxDepth,
+		shadowSamples:             defaultShadowSamples,
+		projection:                Perspective{},
+		sampler:                   Sampler.NewRandom(0),
+		integrator:                Whitted{},
+		background:                Sky{},
+	}
+	c.updateScreenCellMatrix()
+	return c
+}
+
+// updateScreenCellMatrix recomputes the camera space heading through the center of
+// every pixel, pixels the projection doesn't cover get a zero heading
+func (c *Camera) updateScreenCellMatrix() {
+	heightMatrix := getScreenMatrix(float64(c.height))
+	widthMatrix := getScreenMatrix(float64(c.width))
+
+	c.ScreenCellMatrix = make([][]*Vector.Vector, c.height)
+	for row, y := range heightMatrix {
+		c.ScreenCellMatrix[row] = make([]*Vector.Vector, c.width)
+		for col, x := range widthMatrix {
+			_, heading, _ := c.projection.Project(x, y, c.width, c.height)
+			c.ScreenCellMatrix[row][col] = &heading
+		}
+	}
+}
+
+// SetProjection changes how pixels map to rays, the camera starts with a perspective projection
+func (c *Camera) SetProjection(projection Projection) {
+	c.projection = projection
+	c.updateScreenCellMatrix()
+}
+
+func (c *Camera) GetProjection() Projection {
+	return c.projection
+}
+
+func (c *Camera) Width() int {
+	return c.width
+}
+
+func (c *Camera) Height() int {
+	return c.height
+}
+
+// SetFieldOfView switches to a perspective projection with the horizontal field of view
+// in radians, the vertical one follows from the aspect ratio so pixels stay square.
+// 0 restores the original projection.
+func (c *Camera) SetFieldOfView(horizontal float64) {
+	if horizontal == 0 {
+		c.SetVerticalFieldOfView(0)
+		return
+	}
+	aspect := float64(c.height) / float64(c.width)
+	c.SetVerticalFieldOfView(2 * math.Atan(math.Tan(horizontal/2)*aspect))
+}
+
+// SetVerticalFieldOfView switches to a perspective projection with the vertical field of view in radians
+func (c *Camera) SetVerticalFieldOfView(vertical float64) {
+	c.SetProjection(Perspective{VerticalFieldOfView: vertical})
+}
+
+// FieldOfView returns the horizontal and vertical field of view of a perspective
+// projection in radians, measured between the outer edges of the image. Other
+// projections return zeros.
+func (c *Camera) FieldOfView() (horizontal, vertical float64) {
+	perspective, ok := c.projection.(Perspective)
+	if !ok {
+		return 0, 0
+	}
+	if perspective.VerticalFieldOfView == 0 {
+		return 2 * math.Atan(float64(c.width)/float64(2*c.height)), 2 * math.Atan(0.5)
 	}
+	aspect := float64(c.width) / float64(c.height)
+	return 2 * math.Atan(math.Tan(perspective.VerticalFieldOfView/2)*aspect), perspective.VerticalFieldOfView
 }
 
 func (c *Camera) TranslateCamera(vector Vector.Vector) {
@@ -63,13 +164,49 @@ func (c *Camera) RotateCamera(rads float64) {
 	c.UpdateCamRotationTransformer()
 }
 
+// SetOrientation sets the yaw (YRotation), pitch and roll in radians. Positive pitch
+// looks up and roll turns the view about its direction.
+func (c *Camera) SetOrientation(yaw, pitch, roll float64) {
+	c.YRotation = yaw
+	c.Pitch = pitch
+	c.Roll = roll
+	c.UpdateCamRotationTransformer()
+}
+
+// LookAt moves the camera to eye and turns it towards target, rolled so that up
+// points to the top of the screen. World y points down, so up is usually (0, -1, 0).
+func (c *Camera) LookAt(eye, target, up Vector.Vector) {
+	c.CameraPosition = eye
+
+	forward := target.Minus(eye).Normalize()
+	yaw := math.Atan2(forward.X(), forward.Z())
+	pitch := math.Asin(math.Max(-1, math.Min(1, -forward.Y())))
+
+	// roll is measured against where up and right land without any roll
+	unrolled := Vector.RotateBuilder(yaw, pitch, 0)
+	screenUp := unrolled(*Vector.New(0, -1, 0))
+	screenRight := unrolled(*Vector.New(1, 0, 0))
+	up = up.Minus(forward.Scale(up.Dot(forward)))
+	roll := 0.0
+	if up.Dot(up) > 1e-12 {
+		roll = math.Atan2(up.Dot(screenRight), up.Dot(screenUp))
+	}
+
+	c.SetOrientation(yaw, pitch, roll)
+}
+
+// Orient turns a vector from camera space, where the camera looks along +z, into world space
+func (c *Camera) Orient(v Vector.Vector) Vector.Vector {
+	return c.cameraRotationTransformer(v)
+}
+
 func (c *Camera) IncrementForward() {
-	camDirectionVector := Vector.New(0, 0, 1).RotateY(c.YRotation)
+	camDirectionVector := c.cameraRotationTransformer(*Vector.New(0, 0, 1))
 	c.CameraPosition = c.CameraPosition.Translate(camDirectionVector)
 }
 
 func (c *Camera) DecrementForward() {
-	camDirectionVector := Vector.New(0, 0, -1).RotateY(c.YRotation)
+	camDirectionVector := c.cameraRotationTransformer(*Vector.New(0, 0, -1))
 	c.CameraPosition = c.CameraPosition.Translate(camDirectionVector)
 }
 
@@ -83,8 +220,18 @@ func (c *Camera) DecrementYRotation() {
 	c.UpdateCamRotationTransformer()
 }
 
+func (c *Camera) IncrementPitch() {
+	c.Pitch = c.Pitch + math.Pi/32
+	c.UpdateCamRotationTransformer()
+}
+
+func (c *Camera) DecrementPitch() {
+	c.Pitch = c.Pitch - math.Pi/32
+	c.UpdateCamRotationTransformer()
+}
+
 func (c *Camera) UpdateCamRotationTransformer() {
-	c.cameraRotationTransformer = Vector.RotateYBuilder(c.YRotation)
+	c.cameraRotationTransformer = Vector.RotateBuilder(c.YRotation, c.Pitch, c.Roll)
 }
 
 func (c *Camera) GetRotationLine() (x1 float32, y1 float32, x2 float32, y2 float32) {
@@ -94,33 +241,73 @@ func (c *Camera) GetRotationLine() (x1 float32, y1 float32, x2 float32, y2 float
 
 func (c *Camera) SetObject(object Object.Object) {
 	c.ObjectList = append(c.ObjectList, object)
+	c.structure = nil
 }
 
 func (c *Camera) ClearObjects() {
 	c.ObjectList = []Object.Object{}
+	c.structure = nil
+}
+
+// SetLight adds a light. Without any lights surfaces are shaded by how much they face the camera.
+func (c *Camera) SetLight(light Light.Light) {
+	c.Lights = append(c.Lights, light)
+}
+
+func (c *Camera) ClearLights() {
+	c.Lights = []Light.Light{}
+}
+
+// prepareScene builds the acceleration structure and finds the emissive objects if
+// the objects changed since the last cast
+func (c *Camera) prepareScene() {
+	if c.structure == nil {
+		c.structure = Accel.NewBVH(c.ObjectList)
+		c.emitters = emitterLights(c.ObjectList)
+	}
+}
+
+// emitterLights turns the emissive objects into lights. A glowing mesh is a single
+// light picking points over all of its triangles, so its shadow rays don't grow with
+// its triangle count. Objects that can't be sampled, such as planes, are left out.
+func emitterLights(objects []Object.Object) []Light.Light {
+	var lights []Light.Light
+	for _, object := range objects {
+		emitter, ok := object.GetMaterial().(Material.Emitter)
+		if ok && emitter.Emission() != (Vector.Vector{}) && isSampledEmitter(object) {
+			lights = append(lights, Light.NewSurface(object.(Light.Shape), emitter.Emission()))
+			continue
+		}
+		if aggregate, ok := object.(Object.Aggregate); ok {
+			lights = append(lights, emitterLights(aggregate.Primitives())...)
+		}
+	}
+	return lights
+}
+
+// isSampledEmitter reports whether the object lights the scene as one of the
+// camera's emitters when its material glows
+func isSampledEmitter(object Object.Object) bool {
+	_, ok := object.(Light.Shape)
+	return ok
 }
 
 func (c *Camera) CastRays() []Pixel {
+	c.prepareScene()
 	c.pixelList = []Pixel{}
 	c.primaryRays = []Ray.Ray{}
 
 	for yIndex := 0; yIndex < c.height; yIndex++ {
 		for xIndex := 0; xIndex < c.width; xIndex++ {
-			headingVector := c.ScreenCellMatrix[yIndex][xIndex]
-			rotatedVector := c.cameraRotationTransformer(*headingVector)
-
-			primaryRay := Ray.New(c.CameraPosition, rotatedVector)
+			x, y := c.getPixelCenter(xIndex, yIndex)
+			primaryRay, _ := c.getPrimaryRay(x, y)
+			c.primaryRays = append(c.primaryRays, primaryRay)
 
 			var pixel Pixel
 			if c.antiAliasingFactor > 0 {
-				pixel = c.processAntiAliasing(headingVector, xIndex, yIndex, c.antiAliasingFactor)
+				pixel = c.processAntiAliasing(xIndex, yIndex, c.antiAliasingFactor)
 			} else {
-				r, g, b, a := colorVectorToRGB(c.getColor(primaryRay))
-				pixel = Pixel{
-					color: color.RGBA{R: r, G: g, B: b, A: a},
-					x:     xIndex,
-					y:     yIndex,
-				}
+				pixel = newPixel(xIndex, yIndex, c.getCenterSample(xIndex, yIndex))
 			}
 			c.pixelList = append(c.pixelList, pixel)
 		}
@@ -129,59 +316,57 @@ func (c *Camera) CastRays() []Pixel {
 	return c.pixelList
 }
 
+// Render casts rays concurrently into a linear framebuffer
+func (c *Camera) Render() *Film.Film {
+	film, _ := c.RenderContext(context.Background())
+	return film
+}
+
+// RenderContext is Render stopping early when ctx is cancelled, the film then holds
+// black where no rays were cast
+func (c *Camera) RenderContext(ctx context.Context) (*Film.Film, error) {
+	pixels, err := c.CastRaysContext(ctx)
+	film := Film.New(c.width, c.height)
+	for _, p := range pixels {
+		radiance := p.Radiance()
+		film.Set(p.X(), p.Y(), float32(radiance.X()), float32(radiance.Y()), float32(radiance.Z()))
+	}
+	return film, err
+}
+
+// GetPrimaryRays returns the ray through the center of each pixel from the last cast, in row order
 func (c *Camera) GetPrimaryRays() []Ray.Ray {
 	return c.primaryRays
 }
 
 func (c *Camera) CastRaysConcurrent() []Pixel {
-	c.pixelList = []Pixel{}
-
-	rayWorker := func(wg *sync.WaitGroup, list []Pixel, xStart, xEnd, yStart, yEnd int) {
-		defer wg.Done()
-
-		for y := yStart; y < yEnd; y++ {
-			for x := xStart; x < xEnd; x++ {
-				headingVector := c.ScreenCellMatrix[y][x]
-				rotatedVector := c.cameraRotationTransformer(*headingVector)
-
-				primaryRay := Ray.New(c.CameraPosition, rotatedVector)
-
-				var pixel Pixel
-				if c.antiAliasingFactor > 0 {
-					pixel = c.processAntiAliasing(headingVector, x, y, c.antiAliasingFactor)
-				} else {
-					r, g, b, a := colorVectorToRGB(c.getColor(primaryRay))
-					pixel = Pixel{
-						color: color.RGBA{R: r, G: g, B: b, A: a},
-						x:     x,
-						y:     y,
-					}
-				}
-				list[y*c.width+x] = pixel
-			}
-		}
-	}
+	pixels, _ := c.CastRaysContext(context.Background())
+	return pixels
+}
 
-	var wg sync.WaitGroup
+// CastRaysContext casts rays on all cores. When ctx is cancelled it stops early and
+// returns the error of ctx, pixels that weren't cast are left zero.
+func (c *Camera) CastRaysContext(ctx context.Context) ([]Pixel, error) {
+	c.prepareScene()
+	c.primaryRays = make([]Ray.Ray, c.width*c.height)
 	list := make([]Pixel, c.width*c.height)
 
-	cpuSplitFactor := 4
+	err := c.forEachPixel(ctx, func(x, y int) {
+		centerX, centerY := c.getPixelCenter(x, y)
+		primaryRay, _ := c.getPrimaryRay(centerX, centerY)
+		c.primaryRays[y*c.width+x] = primaryRay
 
-	for c.height%cpuSplitFactor != 0 && cpuSplitFactor != 1 {
-		cpuSplitFactor--
-	}
-
-	yPixelGroupSize := c.height / cpuSplitFactor
-	xPixelGroupSize := c.width / cpuSplitFactor
-	for yStart := 0; yStart < c.height; yStart += yPixelGroupSize {
-		for xStart := 0; xStart < c.width; xStart += xPixelGroupSize {
-			wg.Add(1)
-			go rayWorker(&wg, list, xStart, xStart+xPixelGroupSize, yStart, yStart+yPixelGroupSize)
+		var pixel Pixel
+		if c.antiAliasingFactor > 0 {
+			pixel = c.processAntiAliasing(x, y, c.antiAliasingFactor)
+		} else {
+			pixel = newPixel(x, y, c.getCenterSample(x, y))
 		}
-	}
+		list[y*c.width+x] = pixel
+	})
 
-	wg.Wait()
-	return list
+	c.pixelList = list
+	return list, err
 }
 
 // get heading vector for pixel from
@@ -198,10 +383,23 @@ func GetPixelHeadingVector(pYOffset, pXOffset, zoomUnit float64) *Vector.Vector
 	return Vector.New(x, y, z)
 }
 
+// Pixel holds the linear radiance reaching the camera through a pixel, 1 being the
+// brightest a display shows, and that radiance clamped to 8 bits
 type Pixel struct {
-	color color.Color
-	x     int
-	y     int
+	color    color.Color
+	radiance Vector.Vector
+	x        int
+	y        int
+}
+
+func newPixel(x, y int, radiance Vector.Vector) Pixel {
+	r, g, b, a := colorVectorToRGB(radiance)
+	return Pixel{
+		color:    color.RGBA{R: r, G: g, B: b, A: a},
+		radiance: radiance,
+		x:        x,
+		y:        y,
+	}
 }
 
 func (p Pixel) Y() int {
@@ -216,61 +414,203 @@ func (p Pixel) Color() color.Color {
 	return p.color
 }
 
-func (c *Camera) getColor(ray Ray.Ray) Vector.Vector {
-	type objectWithDistance struct {
-		object Object.Object
-		t      float64
-	}
-	intersectionObject := objectWithDistance{
-		object: nil,
-		t:      math.MaxFloat64,
+func (p Pixel) Radiance() Vector.Vector {
+	return p.radiance
+}
+
+// getColor traces the ray into the scene, depth counts the bounces taken to get here
+// and samples places the shadow rays towards area lights
+func (c *Camera) getColor(ray Ray.Ray, depth int, samples *Samples) Vector.Vector {
+	c.countRay()
+	object, t, intersects := c.structure.Closest(ray)
+
+	if intersects {
+		return c.getColorFromObject(ray, t, object, depth, samples)
+	} else {
+		return c.background.Radiance(ray.Direction())
 	}
+}
 
-	for _, object := range c.ObjectList {
-		intersects, t := object.IntersectDistance(ray)
+func (c *Camera) getColorFromObject(ray Ray.Ray, t float64, object Object.Object, depth int, samples *Samples) Vector.Vector {
+	hitNormal := Object.GetShadingNormal(object, ray, t)
+	material := getMaterialAt(object, ray, t)
 
-		if intersects && t < intersectionObject.t {
-			intersectionObject = objectWithDistance{
-				object: object,
-				t:      t,
-			}
-		}
+	if refractive, ok := material.(Material.Refractive); ok {
+		return c.getRefraction(ray, t, hitNormal, refractive, depth, samples)
 	}
 
-	if intersectionObject.object != nil {
-		return getColorFromObject(ray, intersectionObject.t, intersectionObject.object)
+	var colorVector Vector.Vector
+	if len(c.Lights) == 0 && c.backgroundLight == nil && len(c.emitters) == 0 {
+		facingRatio := hitNormal.Dot(ray.Direction().Reverse())
+		facingRatio = math.Max(0, facingRatio)
+
+		colorVector = material.Diffuse().Scale(facingRatio)
 	} else {
-		vector := getBackgroundColor(ray)
-		return vector
+		colorVector = c.getLighting(ray, t, hitNormal, material, samples)
+	}
+
+	if emitter, ok := material.(Material.Emitter); ok {
+		colorVector = colorVector.Translate(emitter.Emission())
+	}
+
+	reflectivity := material.Reflectivity()
+	if reflectivity > 0 {
+		reflection := c.getReflection(ray, t, hitNormal, depth, samples)
+		colorVector = colorVector.Scale(1 - reflectivity).Translate(reflection.Scale(reflectivity))
+	}
+	return colorVector
+}
+
+// texturedMaterial is a material with the diffuse color of its texture at one hit point
+type texturedMaterial struct {
+	Material.Material
+	diffuse Vector.Vector
+}
+
+func (m texturedMaterial) Diffuse() Vector.Vector {
+	return m.diffuse
+}
+
+// getMaterialAt is the object's material, with the diffuse color taken from its
+// texture at the hit point t along ray when it has one
+func getMaterialAt(object Object.Object, ray Ray.Ray, t float64) Material.Material {
+	material := object.GetMaterial()
+	if textured, ok := material.(Material.Textured); !ok || textured.Texture() == nil {
+		return material
+	}
+	return texturedMaterial{Material: material, diffuse: Object.GetSurfaceColorAt(object, ray, t)}
+}
+
+// getReflection traces the mirror bounce off the hit point, once maxDepth bounces
+// have been taken nothing more is reflected
+func (c *Camera) getReflection(ray Ray.Ray, t float64, hitNormal Vector.Vector, depth int, samples *Samples) Vector.Vector {
+	if depth >= c.maxDepth {
+		return Vector.Vector{}
+	}
+
+	if hitNormal.Dot(ray.Direction()) > 0 {
+		hitNormal = hitNormal.Reverse()
+	}
+	hitPoint := ray.Origin().Translate(ray.Direction().Scale(t))
+	reflectedRay := Ray.New(hitPoint.Translate(hitNormal.Scale(shadowBias)), ray.Direction().Reflect(hitNormal))
+
+	return c.getColor(reflectedRay, depth+1, samples)
+}
+
+// getRefraction splits the ray at a transparent surface into a reflected and a
+// refracted ray, weighted by the Fresnel reflectance. The normal is flipped when
+// the ray is leaving the object so that it always faces the incoming ray.
+func (c *Camera) getRefraction(ray Ray.Ray, t float64, hitNormal Vector.Vector, material Material.Refractive, depth int, samples *Samples) Vector.Vector {
+	if depth >= c.maxDepth {
+		return Vector.Vector{}
 	}
+
+	direction := ray.Direction().Normalize()
+	etaIncident, etaTransmitted := 1.0, material.IndexOfRefraction()
+	if direction.Dot(hitNormal) > 0 {
+		hitNormal = hitNormal.Reverse()
+		etaIncident, etaTransmitted = etaTransmitted, etaIncident
+	}
+	cosIncident := -direction.Dot(hitNormal)
+	hitPoint := ray.Origin().Translate(ray.Direction().Scale(t))
+
+	reflectance := Material.Fresnel(cosIncident, etaIncident, etaTransmitted)
+	reflectedRay := Ray.New(hitPoint.Translate(hitNormal.Scale(shadowBias)), direction.Reflect(hitNormal))
+	colorVector := c.getColor(reflectedRay, depth+1, samples).Scale(reflectance)
+
+	if refracted, ok := direction.Refract(hitNormal, etaIncident/etaTransmitted); ok && reflectance < 1 {
+		refractedRay := Ray.New(hitPoint.Translate(hitNormal.Scale(-shadowBias)), refracted)
+		transmitted := c.getColor(refractedRay, depth+1, samples).Multiply(material.Tint())
+		colorVector = colorVector.Translate(transmitted.Scale(1 - reflectance))
+	}
+	return colorVector
 }
 
-func getColorFromObject(ray Ray.Ray, t float64, object Object.Object) Vector.Vector {
-	hitNormal := object.GetHitNormal(ray, t)
-	colorVector := object.GetSurfaceColor()
-	facingRatio := hitNormal.Dot(ray.Direction().Reverse())
-	facingRatio = math.Max(0, facingRatio)
+// getLighting evaluates the Blinn-Phong model, the ambient term plus the direct light
+func (c *Camera) getLighting(ray Ray.Ray, t float64, hitNormal Vector.Vector, material Material.Material, samples *Samples) Vector.Vector {
+	// shade the side of the surface the ray arrived on
+	if hitNormal.Dot(ray.Direction()) > 0 {
+		hitNormal = hitNormal.Reverse()
+	}
+	hitPoint := ray.Origin().Translate(ray.Direction().Scale(t))
+	shadowOrigin := hitPoint.Translate(hitNormal.Scale(shadowBias))
 
-	return colorVector.Scale(facingRatio * 255.99)
+	return material.Ambient().Translate(c.getDirectLighting(shadowOrigin, hitNormal, ray.Direction().Reverse(), material, samples))
 }
 
-func getBackgroundColor(ray Ray.Ray) Vector.Vector {
-	dir := ray.Direction()
-	t := dir.Y()*0.5 + 1
-	blue := Vector.New(0.5, 0.7, 1.0)
-	white := Vector.New(1, 1, 1)
-	lerp := white.Scale(1 - t).Translate(blue.Scale(t))
+// getDirectLighting sums the diffuse and specular contribution of every light that
+// isn't blocked by another object. shadowOrigin is the point just off the surface on
+// the side hitNormal points to. Area lights are averaged over shadowSamples points
+// spread over them, the parts of the light hidden from shadowOrigin give the penumbra.
+// A background that is a light and the emissive objects count as more area lights.
+func (c *Camera) getDirectLighting(shadowOrigin, hitNormal, toViewer Vector.Vector, material Material.Material, samples *Samples) Vector.Vector {
+	diffuse := material.Diffuse()
+	specular := material.Specular()
+	hasSpecular := specular != (Vector.Vector{})
+
+	fromLight := func(light Light.Light) Vector.Vector {
+		// lights without an area ignore where the sample falls
+		count, next := 1, func(int) (float64, float64) { return 0.5, 0.5 }
+		if area, ok := light.(Light.AreaLight); ok && area.Area() > 0 {
+			count = c.shadowSamples
+			next = samples.NextSet(count)
+		}
+
+		var sum Vector.Vector
+		for j := 0; j < count; j++ {
+			u, v := next(j)
+			direction, distance, radiance := light.Sample(shadowOrigin, u, v)
 
-	return lerp.Scale(255.99)
+			cosine := hitNormal.Dot(direction)
+			if cosine <= 0 || radiance == (Vector.Vector{}) {
+				continue
+			}
+			c.countRay()
+			// stopping short of the light keeps a lamp from blocking its own light
+			if c.structure.Occluded(Ray.New(shadowOrigin, direction), distance-shadowBias) {
+				continue
+			}
+
+			reflected := diffuse.Scale(cosine)
+			if hasSpecular {
+				halfway := direction.Translate(toViewer).Normalize()
+				highlight := math.Pow(math.Max(0, hitNormal.Dot(halfway)), material.Shininess())
+				reflected = reflected.Translate(specular.Scale(highlight))
+			}
+			sum = sum.Translate(reflected.Multiply(radiance))
+		}
+		return sum.Scale(1 / float64(count))
+	}
+
+	var lighting Vector.Vector
+	for _, light := range c.Lights {
+		lighting = lighting.Translate(fromLight(light))
+	}
+	if c.backgroundLight != nil {
+		lighting = lighting.Translate(fromLight(c.backgroundLight))
+	}
+	for _, light := range c.emitters {
+		lighting = lighting.Translate(fromLight(light))
+	}
+	return lighting
 }
 
+// colorVectorToRGB quantizes a linear color to 8 bits without gamma, channels outside
+// 0 to 1 are clamped rather than wrapping around
 func colorVectorToRGB(colorVector Vector.Vector) (uint8, uint8, uint8, uint8) {
-	r := uint8(colorVector.X())
-	g := uint8(colorVector.Y())
-	b := uint8(colorVector.Z())
+	r := channelToByte(colorVector.X())
+	g := channelToByte(colorVector.Y())
+	b := channelToByte(colorVector.Z())
 	return r, g, b, 255
 }
 
+func channelToByte(channel float64) uint8 {
+	if !(channel > 0) {
+		return 0
+	}
+	return uint8(math.Min(channel, 1) * 255.99)
+}
+
 func getScreenMatrix(scale float64) []float64 {
 	var cells []float64
 	for i := 0.0; i < scale; i++ {
@@ -279,31 +619,151 @@ func getScreenMatrix(scale float64) []float64 {
 	return cells
 }
 
-func (c *Camera) processAntiAliasing(headingVector *Vector.Vector, xIndex, yIndex, aaFactor int) Pixel {
+func (c *Camera) processAntiAliasing(xIndex, yIndex, aaFactor int) Pixel {
 	var pixel Pixel
 	var colorVector Vector.Vector
 	for aa := 0; aa < aaFactor; aa++ {
-		randX := rand.Float64() / float64(aaFactor)
-		randY := rand.Float64() / float64(aaFactor)
-		randomOffset := Vector.New(randX, randY, 0)
-		aaHeadingVector := headingVector.Translate(*randomOffset).Normalize()
-		aaRotatedVector := c.cameraRotationTransformer(aaHeadingVector)
+		colorVector = colorVector.Translate(c.getAntiAliasingSample(xIndex, yIndex, aa))
+	}
+	pixel = newPixel(xIndex, yIndex, colorVector.Scale(1/float64(aaFactor)))
+
+	return pixel
+}
+
+// getAntiAliasingSample traces sample index of a pixel, placed within the pixel and on the lens by the sampler
+func (c *Camera) getAntiAliasingSample(xIndex, yIndex, index int) Vector.Vector {
+	x, y := c.getPixelCenter(xIndex, yIndex)
+	u, v := c.sampler.Sample(xIndex, yIndex, index, pixelDimension)
+	lensU, lensV := c.sampler.Sample(xIndex, yIndex, index, lensDimension)
+	return c.getSampleColor(x+u-0.5, y+v-0.5, lensU, lensV, newSamples(c.sampler, xIndex, yIndex, index))
+}
+
+// getCenterSample traces the single sample through the center of a pixel used without anti-aliasing
+func (c *Camera) getCenterSample(xIndex, yIndex int) Vector.Vector {
+	x, y := c.getPixelCenter(xIndex, yIndex)
+	lensU, lensV := c.sampler.Sample(xIndex, yIndex, 0, lensDimension)
+	return c.getSampleColor(x, y, lensU, lensV, newSamples(c.sampler, xIndex, yIndex, 0))
+}
+
+// SetDepthOfField turns the pinhole into a thin lens of the given radius. Objects
+// focalDistance in front of the camera stay sharp, the rest blurs with the aperture.
+func (c *Camera) SetDepthOfField(apertureRadius, focalDistance float64) {
+	c.apertureRadius = apertureRadius
+	c.focalDistance = focalDistance
+}
 
-		aaRay := Ray.New(c.CameraPosition, aaRotatedVector)
+// getPixelCenter returns the image point at the center of a pixel, measured from the image center
+func (c *Camera) getPixelCenter(xIndex, yIndex int) (float64, float64) {
+	return -(float64(c.width) / 2) + (float64(xIndex) + 0.5), -(float64(c.height) / 2) + (float64(yIndex) + 0.5)
+}
 
-		colorVector = colorVector.Translate(c.getColor(aaRay))
+// getPrimaryRay returns the world space ray the projection casts through an image
+// point, points the projection doesn't cover get a ray without a direction
+func (c *Camera) getPrimaryRay(x, y float64) (Ray.Ray, bool) {
+	origin, direction, ok := c.projection.Project(x, y, c.width, c.height)
+	if !ok {
+		return Ray.New(c.CameraPosition, Vector.Vector{}), false
 	}
-	r, g, b, a := colorVectorToRGB(colorVector.Scale(1 / float64(aaFactor)))
+	origin = c.CameraPosition.Translate(c.cameraRotationTransformer(origin))
+	return Ray.New(origin, c.cameraRotationTransformer(direction)), true
+}
 
-	pixel = Pixel{
-		color: color.RGBA{R: r, G: g, B: b, A: a},
-		x:     xIndex,
-		y:     yIndex,
+// getSampleColor traces one sample through an image point and a point on the lens in
+// the unit square with the integrator, black where the projection covers nothing
+func (c *Camera) getSampleColor(x, y, lensU, lensV float64, samples *Samples) Vector.Vector {
+	ray, ok := c.getPrimaryRay(x, y)
+	if !ok {
+		return Vector.Vector{}
 	}
+	return c.integrator.Radiance(c, c.getLensRay(ray, lensU, lensV), samples)
+}
 
-	return pixel
+// getLensRay spreads a primary ray over the thin lens. The origin is moved to the
+// lens point picked by lensU and lensV and the ray aimed at the point the primary ray
+// crosses the focal plane, so only that plane stays in focus. Rays not heading
+// forward are left alone.
+func (c *Camera) getLensRay(ray Ray.Ray, lensU, lensV float64) Ray.Ray {
+	if c.apertureRadius <= 0 {
+		return ray
+	}
+	forward := c.cameraRotationTransformer(*Vector.New(0, 0, 1))
+	cosine := ray.Direction().Dot(forward)
+	if cosine <= 0 {
+		return ray
+	}
+
+	focalPoint := ray.Origin().Translate(ray.Direction().Scale(c.focalDistance / cosine))
+	lensX, lensY := sampleDisk(lensU, lensV)
+	lensOffset := c.cameraRotationTransformer(*Vector.New(lensX*c.apertureRadius, lensY*c.apertureRadius, 0))
+	origin := ray.Origin().Translate(lensOffset)
+
+	return Ray.New(origin, focalPoint.Minus(origin).Normalize())
+}
+
+// sampleDisk maps a point in the unit square onto the unit disk with the concentric
+// mapping, which keeps evenly spread samples evenly spread
+func sampleDisk(u, v float64) (float64, float64) {
+	u, v = 2*u-1, 2*v-1
+	if u == 0 && v == 0 {
+		return 0, 0
+	}
+
+	var radius, angle float64
+	if math.Abs(u) > math.Abs(v) {
+		radius = u
+		angle = math.Pi / 4 * (v / u)
+	} else {
+		radius = v
+		angle = math.Pi/2 - math.Pi/4*(u/v)
+	}
+	return radius * math.Cos(angle), radius * math.Sin(angle)
+}
+
+// SetSampler picks where anti-aliasing and lens samples fall, the camera starts with
+// random samples seeded with 0. The same sampler and seed always give the same image.
+func (c *Camera) SetSampler(sampler Sampler.Sampler) {
+	c.sampler = sampler
+}
+
+func (c *Camera) GetSampler() Sampler.Sampler {
+	return c.sampler
+}
+
+// SetIntegrator picks how the light along each ray is computed, the camera starts with Whitted
+func (c *Camera) SetIntegrator(integrator Integrator) {
+	c.integrator = integrator
+}
+
+func (c *Camera) GetIntegrator() Integrator {
+	return c.integrator
+}
+
+// SetBackground changes what rays leaving the scene see, the camera starts with Sky.
+// A background that is also a Light.Light lights the scene with shadow rays as well.
+func (c *Camera) SetBackground(background Background) {
+	c.background = background
+	c.backgroundLight, _ = background.(Light.Light)
+}
+
+func (c *Camera) GetBackground() Background {
+	return c.background
 }
 
 func (c *Camera) SetAntiAliasing(aaFactor int) {
 	c.antiAliasingFactor = aaFactor
 }
+
+// SetMaxDepth limits how many times a ray bounces off reflective surfaces
+func (c *Camera) SetMaxDepth(depth int) {
+	c.maxDepth = depth
+}
+
+// SetShadowSamples sets how many shadow rays go towards each area light per hit, more
+// give smoother penumbras. Point and directional lights always take one.
+func (c *Camera) SetShadowSamples(count int) {
+	c.shadowSamples = max(1, count)
+}
+
+func (c *Camera) GetShadowSamples() int {
+	return c.shadowSamples
+}
@@ -1,13 +1,19 @@
 package Camera
 
 import (
+	"goRay/Light"
+	"goRay/Material"
 	"goRay/Object"
+	"goRay/Ray"
+	"goRay/Sampler"
+	"goRay/Texture"
 	"goRay/Vector"
 	color2 "image/color"
 	"math"
 	"math/rand"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestCamera_GetPixelHeadingVector(t *testing.T) {
@@ -198,6 +204,373 @@ func TestObjectRenderOrder(t *testing.T) {
 	}
 }
 
+func TestLightingAndShadows(t *testing.T) {
+	white := *Vector.New(1, 1, 1)
+	black := color2.RGBA{R: 0, G: 0, B: 0, A: 255}
+	wall := Object.NewPlane(*Vector.New(0, 0, 50), *Vector.New(0, 0, -1), white)
+
+	tests := []struct {
+		name          string
+		light         Light.Light
+		occluder      Object.Object
+		expectedColor color2.RGBA
+	}{
+		{
+			name:          "point light reaches the wall",
+			light:         Light.NewPoint(*Vector.New(0, 0, 25), white, 625),
+			expectedColor: color2.RGBA{R: 255, G: 255, B: 255, A: 255},
+		},
+		{
+			name:          "point light blocked by a sphere",
+			light:         Light.NewPoint(*Vector.New(20, 0, 30), white, 800),
+			occluder:      Object.NewSphere(*Vector.New(10, 0, 40), white, 2),
+			expectedColor: black,
+		},
+		{
+			name:          "point light behind the wall",
+			light:         Light.NewPoint(*Vector.New(0, 0, 75), white, 625),
+			expectedColor: black,
+		},
+		{
+			name:          "directional light at 45 degrees",
+			light:         Light.NewDirectional(*Vector.New(-1, 0, 1), white, 1),
+			expectedColor: color2.RGBA{R: 181, G: 181, B: 181, A: 255},
+		},
+		{
+			name:          "directional light blocked by a box",
+			light:         Light.NewDirectional(*Vector.New(-1, 0, 1), white, 1),
+			occluder:      Object.NewAABox(*Vector.New(15, -5, 25), *Vector.New(25, 5, 35), white),
+			expectedColor: black,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			camera := New(1, 1, Vector.Vector{})
+			camera.SetObject(wall)
+			if tt.occluder != nil {
+				camera.SetObject(tt.occluder)
+			}
+			camera.SetLight(tt.light)
+
+			if color := camera.CastRays()[0].Color(); color != tt.expectedColor {
+				t.Errorf("Expected color to be: '%v', but got '%v'", tt.expectedColor, color)
+			}
+		})
+	}
+}
+
+func TestLightingHasNoShadowAcne(t *testing.T) {
+	white := *Vector.New(1, 1, 1)
+
+	camera := New(1, 1, Vector.Vector{})
+	camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 50), white, 5))
+	camera.SetLight(Light.NewPoint(Vector.Vector{}, white, 45*45))
+
+	expectedColor := color2.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if color := camera.CastRays()[0].Color(); color != expectedColor {
+		t.Errorf("Expected the lit side of the sphere to be '%v', but got '%v'", expectedColor, color)
+	}
+}
+
+func TestAreaLightCastsSoftShadows(t *testing.T) {
+	white := *Vector.New(1, 1, 1)
+	normal := *Vector.New(0, 0, -1)
+	// a sphere between the light and the wall, the edge of its hard shadow from a point
+	// light at the light's center is 4.55 from the middle of the wall
+	occluder := Object.NewSphere(*Vector.New(0, 0, 40), white, 3)
+
+	lightingAt := func(light Light.Light, x float64, occluded bool) float64 {
+		camera := New(1, 1, Vector.Vector{})
+		if occluded {
+			camera.SetObject(occluder)
+		}
+		camera.SetLight(light)
+		camera.SetShadowSamples(64)
+		camera.SetSampler(Sampler.NewSobol(1))
+		camera.prepareScene()
+		material := Material.NewPhong(white, Vector.Vector{}, 0, Vector.Vector{})
+		samples := newSamples(camera.sampler, 0, 0, 0)
+		lighting := camera.getDirectLighting(*Vector.New(x, 0, 50), normal, normal, material, samples)
+		return lighting.X()
+	}
+
+	tests := []struct {
+		name     string
+		x        float64
+		min, max float64
+	}{
+		{name: "umbra", x: 0, min: 0, max: 0},
+		{name: "penumbra", x: 4.55, min: 0.2, max: 0.8},
+		{name: "fully lit", x: 12, min: 1, max: 1},
+	}
+
+	sphere := Light.NewSphere(*Vector.New(0, 0, 20), 4, white, 900)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unoccluded := lightingAt(sphere, tt.x, false)
+			fraction := lightingAt(sphere, tt.x, true) / unoccluded
+			if fraction < tt.min-1e-9 || fraction > tt.max+1e-9 {
+				t.Errorf("Expected between %g and %g of the light, got %g", tt.min, tt.max, fraction)
+			}
+		})
+	}
+
+	// a point light at the same place only ever fully lights or shadows the wall
+	point := Light.NewPoint(*Vector.New(0, 0, 20), white, 900)
+	for _, x := range []float64{4, 5} {
+		if fraction := lightingAt(point, x, true) / lightingAt(point, x, false); fraction != 0 && fraction != 1 {
+			t.Errorf("Expected a hard shadow at %g, got %g of the light", x, fraction)
+		}
+	}
+}
+
+func TestSmallSphereLightRendersLikePoint(t *testing.T) {
+	newCamera := func(light Light.Light) *Camera {
+		camera := New(16, 12, Vector.Vector{})
+		camera.SetObject(Object.NewPlane(*Vector.New(0, 0, 50), *Vector.New(0, 0, -1), *Vector.New(0.8, 0.8, 0.8)))
+		camera.SetObject(Object.NewSphere(*Vector.New(5, 0, 40), *Vector.New(0.8, 0.2, 0.2), 4))
+		camera.SetLight(light)
+		return camera
+	}
+
+	point := newCamera(Light.NewPoint(*Vector.New(-10, -5, 20), *Vector.New(1, 1, 1), 900)).CastRays()
+	sphere := newCamera(Light.NewSphere(*Vector.New(-10, -5, 20), 1e-6, *Vector.New(1, 1, 1), 900)).CastRays()
+	for p := range point {
+		if difference := point[p].Radiance().Minus(sphere[p].Radiance()); difference.Dot(difference) > 1e-12 {
+			t.Fatalf("Expected pixel %d to be %v, got %v", p, point[p].Radiance(), sphere[p].Radiance())
+		}
+	}
+}
+
+func TestTexturedWall(t *testing.T) {
+	red, green := *Vector.New(0.8, 0, 0), *Vector.New(0, 0.8, 0)
+	material := Material.NewDiffuse(*Vector.New(1, 1, 1))
+	material.SetTexture(Texture.NewChecker(red, green, 0.5))
+
+	for _, integrator := range []Integrator{Preview{}, Whitted{}, PathTracer{}} {
+		camera := New(16, 12, Vector.Vector{})
+		wall := Object.NewPlane(*Vector.New(0, 0, 50), *Vector.New(0, 0, -1), *Vector.New(1, 1, 1))
+		wall.SetMaterial(material)
+		camera.SetObject(wall)
+		camera.SetLight(Light.NewPoint(Vector.Vector{}, *Vector.New(1, 1, 1), 2500))
+		camera.SetIntegrator(integrator)
+
+		redPixels, greenPixels := 0, 0
+		for _, pixel := range camera.CastRays() {
+			radiance := pixel.Radiance()
+			switch {
+			case radiance.X() > 0 && radiance.Y() == 0 && radiance.Z() == 0:
+				redPixels++
+			case radiance.Y() > 0 && radiance.X() == 0 && radiance.Z() == 0:
+				greenPixels++
+			default:
+				t.Fatalf("%T: Expected every pixel to be lit red or green, got %v", integrator, radiance)
+			}
+		}
+		if redPixels == 0 || greenPixels == 0 {
+			t.Errorf("%T: Expected both checker colors, got %d red and %d green pixels", integrator, redPixels, greenPixels)
+		}
+	}
+}
+
+// rampTexture rises by 1 per unit of u
+type rampTexture struct{}
+
+func (rampTexture) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	return *Vector.New(u, u, u)
+}
+
+func TestBumpMapTiltsShading(t *testing.T) {
+	render := func(material *Material.Phong) Vector.Vector {
+		camera := New(1, 1, Vector.Vector{})
+		wall := Object.NewPlane(*Vector.New(0, 0, 50), *Vector.New(0, 0, -1), *Vector.New(1, 1, 1))
+		wall.SetMaterial(material)
+		camera.SetObject(wall)
+		camera.SetLight(Light.NewPoint(Vector.Vector{}, *Vector.New(1, 1, 1), 1250))
+		return camera.CastRays()[0].Radiance()
+	}
+
+	flat := render(Material.NewDiffuse(*Vector.New(0.8, 0.8, 0.8)))
+	bumpy := Material.NewDiffuse(*Vector.New(0.8, 0.8, 0.8))
+	bumpy.SetNormalMap(Texture.NewBump(rampTexture{}, 1))
+	bumped := render(bumpy)
+
+	// the wall faces the light head on, the bump tilts it away by 45 degrees
+	if ratio := bumped.X() / flat.X(); math.Abs(ratio-math.Sqrt(0.5)) > 1e-4 {
+		t.Errorf("Expected the bump to dim the wall to %g of %v, got %v", math.Sqrt(0.5), flat, bumped)
+	}
+}
+
+func TestBlinnPhongShading(t *testing.T) {
+	red := *Vector.New(0.5, 0, 0)
+	white := *Vector.New(1, 1, 1)
+
+	tests := []struct {
+		name          string
+		material      Material.Material
+		lightPosition Vector.Vector
+		expectedColor color2.RGBA
+	}{
+		{
+			name:          "diffuse only",
+			material:      Material.NewDiffuse(red),
+			lightPosition: Vector.Vector{},
+			expectedColor: color2.RGBA{R: 127, G: 0, B: 0, A: 255},
+		},
+		{
+			name:          "highlight facing the light",
+			material:      Material.NewPhong(red, *Vector.New(0.5, 0.5, 0.5), 32, Vector.Vector{}),
+			lightPosition: Vector.Vector{},
+			expectedColor: color2.RGBA{R: 255, G: 127, B: 127, A: 255},
+		},
+		{
+			name:          "ambient on the unlit side",
+			material:      Material.NewPhong(red, *Vector.New(0.5, 0.5, 0.5), 32, *Vector.New(0.1, 0.1, 0.1)),
+			lightPosition: *Vector.New(0, 0, 100),
+			expectedColor: color2.RGBA{R: 25, G: 25, B: 25, A: 255},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sphere := Object.NewSphere(*Vector.New(0, 0, 50), white, 5)
+			sphere.SetMaterial(tt.material)
+
+			camera := New(1, 1, Vector.Vector{})
+			camera.SetObject(sphere)
+			camera.SetLight(Light.NewPoint(tt.lightPosition, white, 45*45))
+
+			if color := camera.CastRays()[0].Color(); color != tt.expectedColor {
+				t.Errorf("Expected color to be: '%v', but got '%v'", tt.expectedColor, color)
+			}
+		})
+	}
+}
+
+func TestReflection(t *testing.T) {
+	white := *Vector.New(1, 1, 1)
+	red := *Vector.New(1, 0, 0)
+
+	mirror := Material.NewDiffuse(white)
+	mirror.SetReflectivity(1)
+	halfMirror := Material.NewDiffuse(white)
+	halfMirror.SetReflectivity(0.5)
+
+	tests := []struct {
+		name          string
+		material      Material.Material
+		maxDepth      int
+		expectedColor color2.RGBA
+	}{
+		{
+			name:          "mirror shows the sphere behind the camera",
+			material:      mirror,
+			maxDepth:      defaultMaxDepth,
+			expectedColor: color2.RGBA{R: 255, G: 0, B: 0, A: 255},
+		},
+		{
+			name:          "half mirror blends both colors",
+			material:      halfMirror,
+			maxDepth:      defaultMaxDepth,
+			expectedColor: color2.RGBA{R: 255, G: 127, B: 127, A: 255},
+		},
+		{
+			name:          "no bounces allowed",
+			material:      mirror,
+			maxDepth:      0,
+			expectedColor: color2.RGBA{R: 0, G: 0, B: 0, A: 255},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wall := Object.NewPlane(*Vector.New(0, 0, 50), *Vector.New(0, 0, -1), white)
+			wall.SetMaterial(tt.material)
+
+			camera := New(1, 1, Vector.Vector{})
+			camera.SetMaxDepth(tt.maxDepth)
+			camera.SetObject(wall)
+			camera.SetObject(Object.NewSphere(*Vector.New(0, 0, -20), red, 5))
+
+			if color := camera.CastRays()[0].Color(); color != tt.expectedColor {
+				t.Errorf("Expected color to be: '%v', but got '%v'", tt.expectedColor, color)
+			}
+		})
+	}
+}
+
+func TestFacingMirrorsTerminate(t *testing.T) {
+	white := *Vector.New(1, 1, 1)
+	mirror := Material.NewDiffuse(white)
+	mirror.SetReflectivity(1)
+
+	front := Object.NewPlane(*Vector.New(0, 0, 50), *Vector.New(0, 0, -1), white)
+	front.SetMaterial(mirror)
+	back := Object.NewPlane(*Vector.New(0, 0, -50), *Vector.New(0, 0, 1), white)
+	back.SetMaterial(mirror)
+
+	for _, maxDepth := range []int{1, 5, 50} {
+		camera := New(1, 1, Vector.Vector{})
+		camera.SetMaxDepth(maxDepth)
+		camera.SetObject(front)
+		camera.SetObject(back)
+
+		done := make(chan Pixel)
+		go func() {
+			done <- camera.CastRays()[0]
+		}()
+
+		select {
+		case pixel := <-done:
+			// every bounce is a perfect mirror, so once the depth runs out only black is left
+			expectedColor := color2.RGBA{R: 0, G: 0, B: 0, A: 255}
+			if pixel.Color() != expectedColor {
+				t.Errorf("Depth %d: Expected color to be: '%v', but got '%v'", maxDepth, expectedColor, pixel.Color())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Depth %d: Rays bouncing between facing mirrors never terminated", maxDepth)
+		}
+	}
+}
+
+func TestRefractionThroughGlassSphere(t *testing.T) {
+	red := *Vector.New(1, 0, 0)
+	wall := Object.NewPlane(*Vector.New(0, 0, 100), *Vector.New(0, 0, -1), red)
+	glass := Object.NewSphere(*Vector.New(0, 0, 50), red, 5)
+	glass.SetMaterial(Material.NewDielectric(1.5, *Vector.New(1, 1, 1)))
+
+	camera := New(1, 1, Vector.Vector{})
+	camera.SetObject(wall)
+	camera.SetObject(glass)
+
+	// the center ray meets both surfaces head on, so it passes straight through
+	// to the wall apart from the 4% reflected back at each surface
+	r, g, b, _ := camera.CastRays()[0].Color().RGBA()
+	r, g, b = r/0x101, g/0x101, b/0x101
+	if r < 230 || r > 250 {
+		t.Errorf("Expected most of the red wall to show through, got red %d", r)
+	}
+	if g == 0 || g > 15 || b == 0 || b > 20 {
+		t.Errorf("Expected a faint reflection of the sky, got green %d blue %d", g, b)
+	}
+}
+
+func TestRefractionLimitedByDepth(t *testing.T) {
+	white := *Vector.New(1, 1, 1)
+	glass := Object.NewSphere(*Vector.New(0, 0, 50), white, 5)
+	glass.SetMaterial(Material.NewDielectric(1.5, white))
+
+	camera := New(1, 1, Vector.Vector{})
+	camera.SetObject(glass)
+	camera.SetMaxDepth(0)
+
+	expectedColor := color2.RGBA{R: 0, G: 0, B: 0, A: 255}
+	if color := camera.CastRays()[0].Color(); color != expectedColor {
+		t.Errorf("Expected color to be: '%v', but got '%v'", expectedColor, color)
+	}
+}
+
 func TestWalking(t *testing.T) {
 	spherePosition := Vector.New(0, 0, 50)
 	colorVector := *Vector.New(0, 0, 0)
@@ -254,6 +627,334 @@ func TestWalking(t *testing.T) {
 	//back to origin
 }
 
+func TestPitch(t *testing.T) {
+	origin := Vector.New(0, 0, 0)
+	// y points down, so the sphere is straight above the camera
+	sphereAbove := Object.NewSphere(*Vector.New(0, -50, 0), *Vector.New(0, 0, 0), 3)
+
+	tests := []struct {
+		pitch           float64
+		shouldIntersect bool
+	}{
+		{pitch: 0, shouldIntersect: false},
+		{pitch: math.Pi / 2, shouldIntersect: true},
+		{pitch: -math.Pi / 2, shouldIntersect: false},
+	}
+	for i, tt := range tests {
+		camera := New(1, 1, *origin)
+		camera.SetObject(sphereAbove)
+		camera.SetOrientation(0, tt.pitch, 0)
+		backgroundCamera := New(1, 1, *origin)
+		backgroundCamera.SetOrientation(0, tt.pitch, 0)
+
+		checkIntersection(camera, backgroundCamera, t, tt.shouldIntersect, i+1)
+	}
+}
+
+func TestWalkingFollowsPitch(t *testing.T) {
+	camera := New(1, 1, *Vector.New(0, 0, 0))
+	for i := 0; i < 16; i++ {
+		camera.IncrementPitch()
+	}
+	walkForward(camera, 10)
+
+	if want := *Vector.New(0, -10, 0); camera.CameraPosition.DistanceBetween(want) > 1e-9 {
+		t.Errorf("Expected to walk straight up to %v, got %v", want, camera.CameraPosition)
+	}
+}
+
+func TestLookAt(t *testing.T) {
+	down := *Vector.New(0, -1, 0)
+	tests := []struct {
+		eye, target, up Vector.Vector
+		expectedUp      Vector.Vector
+	}{
+		{
+			eye:        *Vector.New(0, 0, 0),
+			target:     *Vector.New(0, 0, 10),
+			up:         down,
+			expectedUp: down,
+		},
+		{
+			eye:        *Vector.New(0, 0, 0),
+			target:     *Vector.New(10, 0, 10),
+			up:         down,
+			expectedUp: down,
+		},
+		{
+			eye:        *Vector.New(0, 0, 0),
+			target:     *Vector.New(0, -10, 10),
+			up:         down,
+			expectedUp: Vector.New(0, -1, -1).Normalize(),
+		},
+		{
+			eye:        *Vector.New(0, 0, 0),
+			target:     *Vector.New(0, 0, 10),
+			up:         *Vector.New(1, 0, 0),
+			expectedUp: *Vector.New(1, 0, 0),
+		},
+		{
+			eye:        *Vector.New(5, 5, 5),
+			target:     *Vector.New(-3, 2, 9),
+			up:         *Vector.New(0, -2, 0),
+			expectedUp: *Vector.New(0, -1, 0),
+		},
+	}
+	for i, tt := range tests {
+		camera := New(1, 1, *Vector.New(0, 0, 0))
+		camera.LookAt(tt.eye, tt.target, tt.up)
+
+		forward := tt.target.Minus(tt.eye).Normalize()
+		expectedUp := tt.expectedUp.Minus(forward.Scale(tt.expectedUp.Dot(forward))).Normalize()
+
+		if camera.CameraPosition != tt.eye {
+			t.Errorf("Test %d: Expected camera at %v, got %v", i+1, tt.eye, camera.CameraPosition)
+		}
+		if got := camera.Orient(*Vector.New(0, 0, 1)); got.DistanceBetween(forward) > 1e-9 {
+			t.Errorf("Test %d: Expected to look along %v, got %v", i+1, forward, got)
+		}
+		if got := camera.Orient(down); got.DistanceBetween(expectedUp) > 1e-9 {
+			t.Errorf("Test %d: Expected the top of the screen along %v, got %v", i+1, expectedUp, got)
+		}
+	}
+}
+
+func TestPrimaryRaysFollowOrientation(t *testing.T) {
+	camera := New(4, 3, *Vector.New(0, 0, 0))
+	camera.TranslateCamera(*Vector.New(1, 2, 3))
+	camera.SetOrientation(0.5, 0.25, -0.75)
+
+	camera.CastRays()
+	sequential := camera.GetPrimaryRays()
+	camera.CastRaysConcurrent()
+	concurrent := camera.GetPrimaryRays()
+
+	if len(sequential) != 12 || len(concurrent) != 12 {
+		t.Fatalf("Expected 12 primary rays, got %d and %d", len(sequential), len(concurrent))
+	}
+	for i := range sequential {
+		heading := camera.ScreenCellMatrix[i/4][i%4]
+		want := camera.Orient(*heading)
+		if sequential[i].Direction() != want || concurrent[i].Direction() != want {
+			t.Errorf("Test %d: Expected direction %v, got %v and %v", i+1, want, sequential[i].Direction(), concurrent[i].Direction())
+		}
+		if *sequential[i].Origin() != camera.CameraPosition || *concurrent[i].Origin() != camera.CameraPosition {
+			t.Errorf("Test %d: Expected rays to start at the camera", i+1)
+		}
+	}
+}
+
+func TestFieldOfViewCornerRays(t *testing.T) {
+	degrees := math.Pi / 180
+	tests := []struct {
+		width, height      int
+		horizontal         float64
+		vertical           float64
+		expectedHorizontal float64
+		expectedVertical   float64
+	}{
+		{width: 100, height: 100, horizontal: 90 * degrees, expectedHorizontal: 90 * degrees, expectedVertical: 90 * degrees},
+		{width: 1920, height: 1080, horizontal: 90 * degrees, expectedHorizontal: 90 * degrees, expectedVertical: 2 * math.Atan(1080.0/1920)},
+		{width: 1920, height: 1080, vertical: 60 * degrees, expectedHorizontal: 2 * math.Atan(math.Tan(30*degrees)*1920/1080), expectedVertical: 60 * degrees},
+		{width: 30, height: 200, horizontal: 20 * degrees, expectedHorizontal: 20 * degrees, expectedVertical: 2 * math.Atan(math.Tan(10*degrees)*200/30)},
+		{width: 7, height: 5, vertical: 150 * degrees, expectedHorizontal: 2 * math.Atan(math.Tan(75*degrees)*7/5), expectedVertical: 150 * degrees},
+	}
+	for i, tt := range tests {
+		camera := New(tt.width, tt.height, *Vector.New(0, 0, 0))
+		if tt.horizontal != 0 {
+			camera.SetFieldOfView(tt.horizontal)
+		} else {
+			camera.SetVerticalFieldOfView(tt.vertical)
+		}
+
+		horizontal, vertical := camera.FieldOfView()
+		if math.Abs(horizontal-tt.expectedHorizontal) > 1e-9 || math.Abs(vertical-tt.expectedVertical) > 1e-9 {
+			t.Errorf("Test %d: Expected a field of view of %v x %v, got %v x %v", i+1, tt.expectedHorizontal, tt.expectedVertical, horizontal, vertical)
+		}
+
+		// the corner pixel centers sit half a pixel inside the edges of the field of view
+		corners := []*Vector.Vector{
+			camera.ScreenCellMatrix[0][0],
+			camera.ScreenCellMatrix[0][tt.width-1],
+			camera.ScreenCellMatrix[tt.height-1][0],
+			camera.ScreenCellMatrix[tt.height-1][tt.width-1],
+		}
+		for _, corner := range corners {
+			toEdgeX := float64(tt.width) / float64(tt.width-1)
+			toEdgeY := float64(tt.height) / float64(tt.height-1)
+			gotHorizontal := 2 * math.Atan(math.Abs(corner.X()/corner.Z())*toEdgeX)
+			gotVertical := 2 * math.Atan(math.Abs(corner.Y()/corner.Z())*toEdgeY)
+			if math.Abs(gotHorizontal-tt.expectedHorizontal) > 1e-9 || math.Abs(gotVertical-tt.expectedVertical) > 1e-9 {
+				t.Errorf("Test %d: Expected corner rays at %v x %v, got %v x %v", i+1, tt.expectedHorizontal, tt.expectedVertical, gotHorizontal, gotVertical)
+			}
+		}
+		if top := camera.ScreenCellMatrix[0][0]; top.Y() >= 0 || top.X() >= 0 {
+			t.Errorf("Test %d: Expected the first pixel to look up and left, got %v", i+1, top)
+		}
+	}
+}
+
+func TestFieldOfViewZeroKeepsOriginalProjection(t *testing.T) {
+	original := New(16, 9, *Vector.New(0, 0, 0))
+	camera := New(16, 9, *Vector.New(0, 0, 0))
+	camera.SetFieldOfView(math.Pi / 2)
+	camera.SetFieldOfView(0)
+
+	if !reflect.DeepEqual(camera.ScreenCellMatrix, original.ScreenCellMatrix) {
+		t.Errorf("Expected the original headings after resetting the field of view")
+	}
+}
+
+func TestDepthOfFieldFocusesOnFocalPlane(t *testing.T) {
+	camera := New(1, 1, *Vector.New(0, 0, 0))
+	camera.TranslateCamera(*Vector.New(3, -2, 1))
+	camera.SetOrientation(0.4, -0.2, 0.3)
+	camera.SetDepthOfField(2, 40)
+
+	heading := *Vector.New(0.1, -0.2, 1)
+	heading = heading.Normalize()
+	pinhole := camera.Orient(heading)
+	focalPoint := camera.CameraPosition.Translate(pinhole.Scale(40 / heading.Z()))
+	viewDirection := camera.Orient(*Vector.New(0, 0, 1))
+
+	for i := 0; i < 100; i++ {
+		ray := camera.getLensRay(Ray.New(camera.CameraPosition, pinhole), rand.Float64(), rand.Float64())
+		offset := ray.Origin().Minus(camera.CameraPosition)
+
+		if offset.DistanceBetween(Vector.Vector{}) > 2+1e-9 {
+			t.Errorf("Test %d: Expected the ray to start on the lens, got an offset of %v", i+1, offset)
+		}
+		if math.Abs(offset.Dot(viewDirection)) > 1e-9 {
+			t.Errorf("Test %d: Expected the lens to face the view direction, got an offset of %v", i+1, offset)
+		}
+		// walk the ray to the focal plane and check it passes through the focal point
+		distance := focalPoint.Minus(*ray.Origin()).Dot(viewDirection) / ray.Direction().Dot(viewDirection)
+		if hit := ray.Origin().Translate(ray.Direction().Scale(distance)); hit.DistanceBetween(focalPoint) > 1e-9 {
+			t.Errorf("Test %d: Expected the ray to pass through %v, got %v", i+1, focalPoint, hit)
+		}
+	}
+}
+
+func TestDepthOfFieldBlursOutOfFocus(t *testing.T) {
+	inFocus := Object.NewSphere(*Vector.New(0, 0, 40), *Vector.New(1, 1, 1), 1)
+	// lens rays spread 4.5 units around the axis by the time they reach this sphere,
+	// so only those through the middle of the lens hit it
+	outOfFocus := Object.NewSphere(*Vector.New(0, 0, 100), *Vector.New(1, 1, 1), 2)
+
+	tests := []struct {
+		aperture     float64
+		sphere       *Object.Sphere
+		shouldAllHit bool
+	}{
+		{aperture: 0, sphere: outOfFocus, shouldAllHit: true},
+		{aperture: 3, sphere: inFocus, shouldAllHit: true},
+		{aperture: 3, sphere: outOfFocus, shouldAllHit: false},
+	}
+	for i, tt := range tests {
+		camera := New(1, 1, *Vector.New(0, 0, 0))
+		camera.SetDepthOfField(tt.aperture, 40)
+
+		// an even grid of points over the lens
+		const gridSize = 16
+		hits := 0
+		for x := 0; x < gridSize; x++ {
+			for y := 0; y < gridSize; y++ {
+				lensU, lensV := (float64(x)+0.5)/gridSize, (float64(y)+0.5)/gridSize
+				if intersects, _ := tt.sphere.IntersectDistance(camera.getLensRay(Ray.New(camera.CameraPosition, *Vector.New(0, 0, 1)), lensU, lensV)); intersects {
+					hits++
+				}
+			}
+		}
+		if tt.shouldAllHit && hits != gridSize*gridSize {
+			t.Errorf("Test %d: Expected every lens ray to hit the sphere, %d of %d did", i+1, hits, gridSize*gridSize)
+		}
+		if !tt.shouldAllHit && (hits == 0 || hits == gridSize*gridSize) {
+			t.Errorf("Test %d: Expected the sphere to be blurred, %d of %d lens rays hit it", i+1, hits, gridSize*gridSize)
+		}
+	}
+}
+
+func TestSampleDisk(t *testing.T) {
+	tests := []struct {
+		u, v   float64
+		radius float64
+	}{
+		{u: 0.5, v: 0.5, radius: 0},
+		{u: 1, v: 0.5, radius: 1},
+		{u: 0, v: 0, radius: 1},
+		{u: 0.75, v: 0.5, radius: 0.5},
+		{u: 0.5, v: 0.25, radius: 0.5},
+	}
+	for i, tt := range tests {
+		x, y := sampleDisk(tt.u, tt.v)
+		if radius := math.Hypot(x, y); math.Abs(radius-tt.radius) > 1e-9 {
+			t.Errorf("Test %d: Expected a radius of %v, got %v", i+1, tt.radius, radius)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if x, y := sampleDisk(rand.Float64(), rand.Float64()); math.Hypot(x, y) > 1+1e-9 {
+			t.Errorf("Expected samples inside the unit disk, got %v, %v", x, y)
+		}
+	}
+}
+
+func TestSeededRendersAreIdentical(t *testing.T) {
+	newCamera := func(sampler Sampler.Sampler) *Camera {
+		camera := New(16, 12, *Vector.New(0, 0, 0))
+		camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 30), *Vector.New(0.8, 0.2, 0.2), 5))
+		camera.SetObject(Object.NewSphere(*Vector.New(8, 2, 60), *Vector.New(0.2, 0.8, 0.2), 6))
+		camera.SetLight(Light.NewDirectional(*Vector.New(-1, 1, 1), *Vector.New(1, 1, 1), 0.8))
+		camera.SetDepthOfField(1, 30)
+		camera.SetAntiAliasing(6)
+		camera.SetSampler(sampler)
+		return camera
+	}
+	samplers := []func(seed int64) Sampler.Sampler{
+		func(seed int64) Sampler.Sampler { return Sampler.NewRandom(seed) },
+		func(seed int64) Sampler.Sampler { return Sampler.NewStratified(6, seed) },
+		func(seed int64) Sampler.Sampler { return Sampler.NewHalton(seed) },
+		func(seed int64) Sampler.Sampler { return Sampler.NewSobol(seed) },
+	}
+
+	for i, sampler := range samplers {
+		first := newCamera(sampler(5)).CastRays()
+		concurrent := newCamera(sampler(5)).CastRaysConcurrent()
+		other := newCamera(sampler(6)).CastRays()
+
+		differs := false
+		for p := range first {
+			if first[p].Color() != concurrent[p].Color() {
+				t.Fatalf("Test %d: Expected pixel %d to match between renders, got %v and %v", i+1, p, first[p].Color(), concurrent[p].Color())
+			}
+			if first[p].Color() != other[p].Color() {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Errorf("Test %d: Expected another seed to change the image", i+1)
+		}
+	}
+}
+
+func TestOverexposedColorsClamp(t *testing.T) {
+	camera := New(1, 1, *Vector.New(0, 0, 0))
+	camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 50), *Vector.New(1, 0.5, 0.1), 5))
+	camera.SetLight(Light.NewDirectional(*Vector.New(0, 0, 1), *Vector.New(1, 1, 1), 3))
+
+	pixel := camera.CastRays()[0]
+	if want := (color2.RGBA{R: 255, G: 255, B: 76, A: 255}); pixel.Color() != want {
+		t.Errorf("Expected the color to clamp to %v, got %v", want, pixel.Color())
+	}
+	if radiance := pixel.Radiance(); math.Abs(radiance.X()-3) > 1e-9 || math.Abs(radiance.Y()-1.5) > 1e-9 {
+		t.Errorf("Expected the radiance to keep values above 1, got %v", radiance)
+	}
+
+	film := camera.Render()
+	if r, g, b := film.At(0, 0); math.Abs(float64(r)-3) > 1e-6 || math.Abs(float64(g)-1.5) > 1e-6 || math.Abs(float64(b)-0.3) > 1e-6 {
+		t.Errorf("Expected the film to hold the radiance, got %v %v %v", r, g, b)
+	}
+}
+
 func BenchmarkCamera_GetPixelHeadingVector(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		GetPixelHeadingVector(10, 10, 1)
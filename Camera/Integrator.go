@@ -0,0 +1,196 @@
+package Camera
+
+import (
+	"fmt"
+	"goRay/Material"
+	"goRay/Object"
+	"goRay/Ray"
+	"goRay/Sampler"
+	"goRay/Vector"
+	"math"
+)
+
+// integratorDimension is the first sampler dimension handed to integrators, the ones
+// before it place the sample in the pixel and on the lens
+const integratorDimension = lensDimension + 1
+
+// rouletteDepth is how many bounces a path takes before Russian roulette may end it
+const rouletteDepth = 3
+
+// maxSurvival caps the chance of a path surviving Russian roulette, so paths between
+// perfect mirrors still end
+const maxSurvival = 0.95
+
+// Integrator computes the light arriving at the camera along a primary ray
+type Integrator interface {
+	Radiance(c *Camera, ray Ray.Ray, samples *Samples) Vector.Vector
+}
+
+// IntegratorNames lists the integrators IntegratorByName knows
+var IntegratorNames = []string{"preview", "whitted", "path"}
+
+func IntegratorByName(name string) (Integrator, error) {
+	switch name {
+	case "preview":
+		return Preview{}, nil
+	case "whitted":
+		return Whitted{}, nil
+	case "path":
+		return PathTracer{}, nil
+	}
+	return nil, fmt.Errorf("unknown integrator %q", name)
+}
+
+// Samples hands an integrator the sampler's points for one camera sample, every
+// call to Next moves on to the next dimension
+type Samples struct {
+	sampler   Sampler.Sampler
+	x, y      int
+	index     int
+	dimension int
+}
+
+func newSamples(sampler Sampler.Sampler, x, y, index int) *Samples {
+	return &Samples{sampler: sampler, x: x, y: y, index: index, dimension: integratorDimension}
+}
+
+// Next returns a point in the unit square
+func (s *Samples) Next() (float64, float64) {
+	u, v := s.sampler.Sample(s.x, s.y, s.index, s.dimension)
+	s.dimension++
+	return u, v
+}
+
+// NextSet moves on to the next dimension and returns a function giving count points
+// of it, spread out as well as the sampler spreads samples within a pixel
+func (s *Samples) NextSet(count int) func(j int) (float64, float64) {
+	dimension := s.dimension
+	s.dimension++
+	return func(j int) (float64, float64) {
+		return s.sampler.Sample(s.x, s.y, s.index*count+j, dimension)
+	}
+}
+
+// Preview shades surfaces by how directly they face the camera, ignoring lights,
+// shadows and bounces. It is the fastest way to see the shape of a scene.
+type Preview struct{}
+
+func (Preview) Radiance(c *Camera, ray Ray.Ray, samples *Samples) Vector.Vector {
+	c.countRay()
+	object, t, intersects := c.structure.Closest(ray)
+	if !intersects {
+		return c.background.Radiance(ray.Direction())
+	}
+	if emitter, ok := object.GetMaterial().(Material.Emitter); ok {
+		return emitter.Emission()
+	}
+
+	facingRatio := math.Max(0, Object.GetShadingNormal(object, ray, t).Dot(ray.Direction().Reverse()))
+	return Object.GetSurfaceColorAt(object, ray, t).Scale(facingRatio)
+}
+
+// Whitted traces light from the lights and emissive objects with Blinn-Phong shading
+// and shadows, and follows mirror and glass bounces. Without either surfaces are
+// shaded by how directly they face the camera.
+type Whitted struct{}
+
+func (Whitted) Radiance(c *Camera, ray Ray.Ray, samples *Samples) Vector.Vector {
+	return c.getColor(ray, 0, samples)
+}
+
+// PathTracer follows random paths of light bouncing between surfaces, which adds the
+// indirect light Whitted leaves out, such as color bleeding between walls and light
+// from emissive planes. Diffuse bounces are cosine weighted and paths are ended by
+// Russian roulette, so the image converges to the right result with more samples.
+// The lights are scaled as in Whitted so both give the same direct light, but the
+// ambient term is left out as the bounces replace it.
+type PathTracer struct{}
+
+func (PathTracer) Radiance(c *Camera, ray Ray.Ray, samples *Samples) Vector.Vector {
+	var radiance Vector.Vector
+	throughput := *Vector.New(1, 1, 1)
+	// the direct light of a diffuse bounce already sampled the background when it
+	// lights the scene and the emissive objects, seeing them again after one would
+	// count them twice
+	directSampled := false
+
+	for depth := 0; ; depth++ {
+		c.countRay()
+		object, t, intersects := c.structure.Closest(ray)
+		if !intersects {
+			if directSampled && c.backgroundLight != nil {
+				return radiance
+			}
+			return radiance.Translate(throughput.Multiply(c.background.Radiance(ray.Direction())))
+		}
+
+		material := getMaterialAt(object, ray, t)
+		if emitter, ok := material.(Material.Emitter); ok && !(directSampled && isSampledEmitter(object)) {
+			radiance = radiance.Translate(throughput.Multiply(emitter.Emission()))
+		}
+		if depth >= c.maxDepth {
+			return radiance
+		}
+
+		hitNormal := Object.GetShadingNormal(object, ray, t)
+		direction := ray.Direction().Normalize()
+		hitPoint := ray.Origin().Translate(ray.Direction().Scale(t))
+		choice, _ := samples.Next()
+
+		if refractive, ok := material.(Material.Refractive); ok {
+			// follow either the reflected or the refracted ray, picked by the Fresnel reflectance
+			etaIncident, etaTransmitted := 1.0, refractive.IndexOfRefraction()
+			if direction.Dot(hitNormal) > 0 {
+				hitNormal = hitNormal.Reverse()
+				etaIncident, etaTransmitted = etaTransmitted, etaIncident
+			}
+			reflectance := Material.Fresnel(-direction.Dot(hitNormal), etaIncident, etaTransmitted)
+			refracted, ok := direction.Refract(hitNormal, etaIncident/etaTransmitted)
+			directSampled = false
+			if !ok || choice < reflectance {
+				ray = Ray.New(hitPoint.Translate(hitNormal.Scale(shadowBias)), direction.Reflect(hitNormal))
+			} else {
+				ray = Ray.New(hitPoint.Translate(hitNormal.Scale(-shadowBias)), refracted)
+				throughput = throughput.Multiply(refractive.Tint())
+			}
+		} else {
+			if hitNormal.Dot(direction) > 0 {
+				hitNormal = hitNormal.Reverse()
+			}
+			origin := hitPoint.Translate(hitNormal.Scale(shadowBias))
+
+			if choice < material.Reflectivity() {
+				ray = Ray.New(origin, direction.Reflect(hitNormal))
+				directSampled = false
+			} else {
+				direct := c.getDirectLighting(origin, hitNormal, direction.Reverse(), material, samples)
+				radiance = radiance.Translate(throughput.Multiply(direct))
+
+				// the cosine weighted directions cancel the cosine and 1/pi of the
+				// diffuse reflection, leaving the diffuse color as the weight
+				u, v := samples.Next()
+				ray = Ray.New(origin, sampleCosineHemisphere(hitNormal, u, v))
+				throughput = throughput.Multiply(material.Diffuse())
+				directSampled = true
+			}
+		}
+
+		if depth+1 >= rouletteDepth {
+			survival := math.Min(maxSurvival, math.Max(throughput.X(), math.Max(throughput.Y(), throughput.Z())))
+			if roll, _ := samples.Next(); roll >= survival {
+				return radiance
+			}
+			throughput = throughput.Scale(1 / survival)
+		}
+	}
+}
+
+// sampleCosineHemisphere maps a point in the unit square to a direction around normal,
+// directions close to the normal being picked more often in proportion to their cosine
+func sampleCosineHemisphere(normal Vector.Vector, u, v float64) Vector.Vector {
+	x, y := sampleDisk(u, v)
+	z := math.Sqrt(math.Max(0, 1-x*x-y*y))
+
+	tangent, bitangent := normal.OrthonormalBasis()
+	return tangent.Scale(x).Translate(bitangent.Scale(y)).Translate(normal.Scale(z)).Normalize()
+}
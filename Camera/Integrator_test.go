@@ -0,0 +1,290 @@
+package Camera
+
+import (
+	"goRay/Light"
+	"goRay/Material"
+	"goRay/Object"
+	"goRay/Sampler"
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+// glowingMaterial reflects light diffusely and gives off light of its own
+type glowingMaterial struct {
+	*Material.Phong
+	emission Vector.Vector
+}
+
+func (g glowingMaterial) Emission() Vector.Vector {
+	return g.emission
+}
+
+func averageRadiance(pixels []Pixel) Vector.Vector {
+	var sum Vector.Vector
+	for _, p := range pixels {
+		sum = sum.Translate(p.Radiance())
+	}
+	return sum.Scale(1 / float64(len(pixels)))
+}
+
+func TestPathTracerFurnace(t *testing.T) {
+	// inside a closed sphere that emits e and reflects a share a of the light, the light
+	// bouncing around converges to e/(1-a) in every direction
+	tests := []struct {
+		emission, albedo float64
+	}{
+		{emission: 0.5, albedo: 0.5},
+		{emission: 0.2, albedo: 0.8},
+		{emission: 1, albedo: 0},
+	}
+
+	for i, tt := range tests {
+		enclosure := Object.NewSphere(*Vector.New(0, 0, 0), *Vector.New(1, 1, 1), 100)
+		diffuse := *Vector.New(tt.albedo, tt.albedo, tt.albedo)
+		enclosure.SetMaterial(glowingMaterial{Phong: Material.NewDiffuse(diffuse), emission: *Vector.New(tt.emission, tt.emission, tt.emission)})
+
+		camera := New(16, 16, *Vector.New(0, 0, 0))
+		camera.SetObject(enclosure)
+		camera.SetIntegrator(PathTracer{})
+		camera.SetMaxDepth(1000)
+		camera.SetAntiAliasing(16)
+		camera.SetSampler(Sampler.NewRandom(1))
+
+		expected := tt.emission / (1 - tt.albedo)
+		average := averageRadiance(camera.CastRaysConcurrent())
+		for _, channel := range []float64{average.X(), average.Y(), average.Z()} {
+			if math.Abs(channel-expected) > 0.02*expected {
+				t.Errorf("Test %d: Expected an average of %v, got %v", i+1, expected, average)
+				break
+			}
+		}
+	}
+}
+
+func TestPathTracerLightsFromEmissiveSurfaces(t *testing.T) {
+	render := func(integrator Integrator, emission float64) Vector.Vector {
+		camera := New(4, 4, *Vector.New(0, 0, 0))
+		camera.SetObject(Object.NewPlane(*Vector.New(0, 0, 30), *Vector.New(0, 0, -1), *Vector.New(0.8, 0.8, 0.8)))
+		// a glowing sphere behind the camera lights the wall in front of it
+		lamp := Object.NewSphere(*Vector.New(0, 0, -20), *Vector.New(1, 1, 1), 10)
+		lamp.SetMaterial(Material.NewEmissive(*Vector.New(emission, emission, emission)))
+		camera.SetObject(lamp)
+		// the light comes in at an angle so the lamp doesn't shadow the wall
+		camera.SetLight(Light.NewDirectional(*Vector.New(0.5, 0, 1), *Vector.New(1, 1, 1), 0.5))
+		camera.SetIntegrator(integrator)
+		camera.SetAntiAliasing(64)
+		camera.SetSampler(Sampler.NewSobol(2))
+		return averageRadiance(camera.CastRaysConcurrent())
+	}
+
+	dark, lit := render(PathTracer{}, 0), render(PathTracer{}, 50)
+	if lit.X() < dark.X()+0.1 {
+		t.Errorf("Expected the glowing sphere to light the wall, got %v with it and %v without", lit, dark)
+	}
+	if direct := 0.8 * 0.5 / math.Sqrt(1.25); dark.X() < direct {
+		t.Errorf("Expected at least the direct light of %v, got %v", direct, dark)
+	}
+
+	// the wall can't light itself, so the path tracer adds nothing to the light Whitted
+	// gets from the lamp. Bounces hitting the lamp after its light was sampled directly
+	// would count it twice.
+	whittedDark, whittedLit := render(Whitted{}, 0), render(Whitted{}, 50)
+	fromLamp, whittedFromLamp := lit.X()-dark.X(), whittedLit.X()-whittedDark.X()
+	if math.Abs(fromLamp-whittedFromLamp) > 0.02*whittedFromLamp {
+		t.Errorf("Expected the lamp to add %v to the wall as in Whitted, got %v", whittedFromLamp, fromLamp)
+	}
+}
+
+func TestEmissiveObjectsLightTheScene(t *testing.T) {
+	glow := Material.NewEmissive(*Vector.New(50, 50, 50))
+	sphere := Object.NewSphere(*Vector.New(0, 0, -20), *Vector.New(1, 1, 1), 10)
+	sphere.SetMaterial(glow)
+	// a square of two triangles, facing away from the wall
+	corners := []Vector.Vector{*Vector.New(-5, -5, -20), *Vector.New(5, -5, -20), *Vector.New(5, 5, -20), *Vector.New(-5, 5, -20)}
+	mesh := Object.NewMesh([]*Object.Triangle{
+		Object.NewTriangle(corners[0], corners[1], corners[2], *Vector.New(1, 1, 1)),
+		Object.NewTriangle(corners[0], corners[2], corners[3], *Vector.New(1, 1, 1)),
+	}, *Vector.New(1, 1, 1))
+	mesh.SetMaterial(glow)
+
+	// the wall straight ahead of the camera sees either lamp straight on from 50 away
+	tests := []struct {
+		lamp     Object.Object
+		expected float64
+	}{
+		// a sphere covers the solid angle of a disc of its radius, which reflects
+		// diffuse E r^2/d^2 on the axis
+		{lamp: sphere, expected: 0.8 * 50 * 100 / 2500},
+		{lamp: mesh, expected: 0.8 * 50 * squareFormFactor(10, 50)},
+	}
+
+	for i, tt := range tests {
+		for _, integrator := range []Integrator{Whitted{}, PathTracer{}} {
+			camera := New(1, 1, *Vector.New(0, 0, 0))
+			camera.SetObject(Object.NewPlane(*Vector.New(0, 0, 30), *Vector.New(0, 0, -1), *Vector.New(0.8, 0.8, 0.8)))
+			camera.SetObject(tt.lamp)
+			camera.SetIntegrator(integrator)
+			// nothing but the lamp lights the wall
+			camera.SetBackground(darkness{})
+			camera.SetShadowSamples(1024)
+			camera.SetSampler(Sampler.NewSobol(3))
+
+			average := averageRadiance(camera.CastRaysConcurrent())
+			got := average.X()
+			if math.Abs(got-tt.expected) > 0.02*tt.expected {
+				t.Errorf("Test %d: Expected %T to light the wall with %g, got %g", i, integrator, tt.expected, got)
+			}
+		}
+	}
+}
+
+func TestEmissiveMeshIsOneLight(t *testing.T) {
+	// the square of the test above cut into triangles of uneven sizes
+	const cuts = 6
+	at := func(i int) float64 {
+		share := float64(i) / cuts
+		return -5 + 10*share*share
+	}
+	var triangles []*Object.Triangle
+	for i := 0; i < cuts; i++ {
+		for j := 0; j < cuts; j++ {
+			corners := []Vector.Vector{*Vector.New(at(i), at(j), -20), *Vector.New(at(i+1), at(j), -20), *Vector.New(at(i+1), at(j+1), -20), *Vector.New(at(i), at(j+1), -20)}
+			triangles = append(triangles,
+				Object.NewTriangle(corners[0], corners[1], corners[2], *Vector.New(1, 1, 1)),
+				Object.NewTriangle(corners[0], corners[2], corners[3], *Vector.New(1, 1, 1)))
+		}
+	}
+	mesh := Object.NewMesh(triangles, *Vector.New(1, 1, 1))
+	mesh.SetMaterial(Material.NewEmissive(*Vector.New(50, 50, 50)))
+
+	camera := New(1, 1, *Vector.New(0, 0, 0))
+	camera.SetObject(Object.NewPlane(*Vector.New(0, 0, 30), *Vector.New(0, 0, -1), *Vector.New(0.8, 0.8, 0.8)))
+	camera.SetObject(mesh)
+	camera.SetBackground(darkness{})
+	camera.SetShadowSamples(1024)
+	camera.SetSampler(Sampler.NewSobol(3))
+
+	average := averageRadiance(camera.CastRaysConcurrent())
+	if len(camera.emitters) != 1 {
+		t.Errorf("Expected the mesh of %d triangles to be a single light, got %d", len(triangles), len(camera.emitters))
+	}
+	if expected, got := 0.8*50*squareFormFactor(10, 50), average.X(); math.Abs(got-expected) > 0.02*expected {
+		t.Errorf("Expected the mesh to light the wall with %g, got %g", expected, got)
+	}
+}
+
+// darkness is a background that gives off no light
+type darkness struct{}
+
+func (darkness) Radiance(Vector.Vector) Vector.Vector {
+	return Vector.Vector{}
+}
+
+// squareFormFactor is the share of the light a point sees from a square of side a
+// facing it on its axis, distance away
+func squareFormFactor(a, distance float64) float64 {
+	x := a / 2 / distance
+	s := x / math.Sqrt(1+x*x)
+	return 4 / math.Pi * s * math.Atan(s)
+}
+
+func TestPreviewMatchesUnlitWhitted(t *testing.T) {
+	newCamera := func(integrator Integrator) *Camera {
+		camera := New(16, 12, *Vector.New(0, 0, 0))
+		camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 30), *Vector.New(0.8, 0.2, 0.2), 5))
+		camera.SetObject(Object.NewAABox(*Vector.New(5, -5, 40), *Vector.New(15, 5, 50), *Vector.New(0.2, 0.8, 0.2)))
+		camera.SetIntegrator(integrator)
+		return camera
+	}
+
+	preview := newCamera(Preview{}).CastRays()
+	whitted := newCamera(Whitted{}).CastRays()
+	for p := range preview {
+		if preview[p] != whitted[p] {
+			t.Fatalf("Expected pixel %d to be %v, got %v", p, whitted[p].Radiance(), preview[p].Radiance())
+		}
+	}
+
+	// the preview doesn't light the scene
+	lit := newCamera(Preview{})
+	lit.SetLight(Light.NewDirectional(*Vector.New(0, 0, 1), *Vector.New(1, 1, 1), 3))
+	for p, pixel := range lit.CastRays() {
+		if pixel != preview[p] {
+			t.Fatalf("Expected lights not to change the preview at pixel %d", p)
+		}
+	}
+}
+
+func TestIntegratorByName(t *testing.T) {
+	for _, name := range IntegratorNames {
+		if _, err := IntegratorByName(name); err != nil {
+			t.Errorf("Expected %s to be known, got %v", name, err)
+		}
+	}
+	if _, err := IntegratorByName("bidirectional"); err == nil {
+		t.Errorf("Expected an error for an unknown integrator")
+	}
+}
+
+func TestSamplesMoveThroughDimensions(t *testing.T) {
+	sampler := Sampler.NewHalton(4)
+	samples := newSamples(sampler, 3, 5, 7)
+	for dimension := integratorDimension; dimension < integratorDimension+4; dimension++ {
+		u, v := samples.Next()
+		expectedU, expectedV := sampler.Sample(3, 5, 7, dimension)
+		if u != expectedU || v != expectedV {
+			t.Errorf("Expected dimension %d to give %v, %v, got %v, %v", dimension, expectedU, expectedV, u, v)
+		}
+	}
+}
+
+func TestSamplesNextSet(t *testing.T) {
+	sampler := Sampler.NewSobol(4)
+	samples := newSamples(sampler, 3, 5, 2)
+	next := samples.NextSet(8)
+	for j := 0; j < 8; j++ {
+		u, v := next(j)
+		expectedU, expectedV := sampler.Sample(3, 5, 2*8+j, integratorDimension)
+		if u != expectedU || v != expectedV {
+			t.Errorf("Expected point %d to be sample %d, got %v, %v", j, 2*8+j, u, v)
+		}
+	}
+
+	// the set takes up a single dimension
+	u, v := samples.Next()
+	if expectedU, expectedV := sampler.Sample(3, 5, 2, integratorDimension+1); u != expectedU || v != expectedV {
+		t.Errorf("Expected the next dimension after the set, got %v, %v", u, v)
+	}
+}
+
+func TestSampleCosineHemisphere(t *testing.T) {
+	normals := []Vector.Vector{
+		*Vector.New(0, 0, 1),
+		*Vector.New(1, 0, 0),
+		*Vector.New(0, -1, 0),
+		Vector.New(1, 2, -3).Normalize(),
+	}
+	sampler := Sampler.NewSobol(3)
+
+	for i, normal := range normals {
+		sumCosine := 0.0
+		const count = 4096
+		for index := 0; index < count; index++ {
+			u, v := sampler.Sample(0, 0, index, 0)
+			direction := sampleCosineHemisphere(normal, u, v)
+			if d := direction.Dot(direction); math.Abs(d-1) > 1e-9 {
+				t.Fatalf("Test %d: Expected a unit direction, got %v", i+1, direction)
+			}
+			cosine := direction.Dot(normal)
+			if cosine < 0 {
+				t.Fatalf("Test %d: Expected directions on the side of the normal, got %v", i+1, direction)
+			}
+			sumCosine += cosine
+		}
+		// the cosine of cosine weighted directions averages 2/3
+		if mean := sumCosine / count; math.Abs(mean-2.0/3) > 0.005 {
+			t.Errorf("Test %d: Expected a mean cosine of 2/3, got %v", i+1, mean)
+		}
+	}
+}
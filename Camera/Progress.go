@@ -0,0 +1,68 @@
+package Camera
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Progress describes how far a cast has come
+type Progress struct {
+	TilesDone int
+	Tiles     int
+	// Rays counts every ray traced so far, camera rays as well as bounces and shadow rays
+	Rays    int64
+	Elapsed time.Duration
+	// Remaining estimates the time left from the time the finished tiles took
+	Remaining time.Duration
+}
+
+// Fraction returns how much of the image is done, from 0 to 1
+func (p Progress) Fraction() float64 {
+	if p.Tiles == 0 {
+		return 1
+	}
+	return float64(p.TilesDone) / float64(p.Tiles)
+}
+
+// String formats the progress as a status line like "50% 3/6 tiles 1.2M rays 1.5s elapsed 1.5s left"
+func (p Progress) String() string {
+	return fmt.Sprintf("%3.0f%% %d/%d tiles %s rays %v elapsed %v left", p.Fraction()*100, p.TilesDone, p.Tiles,
+		formatCount(p.Rays), p.Elapsed.Round(100*time.Millisecond), p.Remaining.Round(100*time.Millisecond))
+}
+
+// formatCount shortens large counts with a k, M or G suffix
+func formatCount(n int64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1fG", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprint(n)
+}
+
+// ProgressObserver is called after every finished tile. Calls come from the workers
+// but never overlap, so observers don't need locking, they should return quickly.
+type ProgressObserver func(Progress)
+
+// SetProgressObserver reports the progress of every following cast to observer, nil turns reporting off
+func (c *Camera) SetProgressObserver(observer ProgressObserver) {
+	c.progressObserver = observer
+}
+
+// countRay is called for every ray traced, it is safe to call from the workers
+func (c *Camera) countRay() {
+	atomic.AddInt64(&c.rays, 1)
+}
+
+// newProgress estimates the remaining time assuming the tiles left take as long as the finished ones
+func newProgress(tilesDone, tiles int, rays int64, elapsed time.Duration) Progress {
+	progress := Progress{TilesDone: tilesDone, Tiles: tiles, Rays: rays, Elapsed: elapsed}
+	if tilesDone > 0 {
+		progress.Remaining = time.Duration(float64(elapsed) * float64(tiles-tilesDone) / float64(tilesDone))
+	}
+	return progress
+}
@@ -0,0 +1,107 @@
+package Camera
+
+import (
+	"goRay/Object"
+	"goRay/Vector"
+	"testing"
+	"time"
+)
+
+func TestProgressObserver(t *testing.T) {
+	camera := New(40, 20, *Vector.New(0, 0, 0))
+	camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 30), *Vector.New(1, 1, 1), 5))
+
+	var reports []Progress
+	camera.SetProgressObserver(func(progress Progress) {
+		reports = append(reports, progress)
+	})
+	camera.CastRaysConcurrent()
+
+	// 40x20 pixels are 3 by 2 tiles
+	if len(reports) != 6 {
+		t.Fatalf("Expected a report for each of the 6 tiles, got %d", len(reports))
+	}
+	for i, progress := range reports {
+		if progress.TilesDone != i+1 || progress.Tiles != 6 {
+			t.Errorf("Test %d: Expected %d of 6 tiles, got %d of %d", i+1, i+1, progress.TilesDone, progress.Tiles)
+		}
+		if i > 0 && (progress.Rays < reports[i-1].Rays || progress.Elapsed < reports[i-1].Elapsed) {
+			t.Errorf("Test %d: Expected rays and time to only grow, got %+v after %+v", i+1, progress, reports[i-1])
+		}
+	}
+	last := reports[len(reports)-1]
+	if last.Rays != 40*20 {
+		t.Errorf("Expected one ray per pixel, got %d", last.Rays)
+	}
+	if last.Remaining != 0 || last.Fraction() != 1 {
+		t.Errorf("Expected nothing to remain, got %v and %v", last.Remaining, last.Fraction())
+	}
+
+	// every cast counts from zero
+	reports = nil
+	camera.SetAntiAliasing(2)
+	camera.CastRaysConcurrent()
+	if rays := reports[len(reports)-1].Rays; rays != 2*40*20 {
+		t.Errorf("Expected two rays per pixel, got %d", rays)
+	}
+
+	reports = nil
+	camera.SetProgressObserver(nil)
+	camera.CastRaysConcurrent()
+	if len(reports) != 0 {
+		t.Errorf("Expected no reports after removing the observer, got %d", len(reports))
+	}
+}
+
+func TestNewProgress(t *testing.T) {
+	tests := []struct {
+		tilesDone, tiles int
+		elapsed          time.Duration
+		remaining        time.Duration
+		fraction         float64
+	}{
+		{tilesDone: 1, tiles: 4, elapsed: time.Second, remaining: 3 * time.Second, fraction: 0.25},
+		{tilesDone: 3, tiles: 4, elapsed: 3 * time.Second, remaining: time.Second, fraction: 0.75},
+		{tilesDone: 4, tiles: 4, elapsed: time.Minute, remaining: 0, fraction: 1},
+		{tilesDone: 0, tiles: 4, elapsed: time.Second, remaining: 0, fraction: 0},
+		{tilesDone: 0, tiles: 0, remaining: 0, fraction: 1},
+	}
+	for i, tt := range tests {
+		progress := newProgress(tt.tilesDone, tt.tiles, 10, tt.elapsed)
+		if progress.Remaining != tt.remaining {
+			t.Errorf("Test %d: Expected %v remaining, got %v", i+1, tt.remaining, progress.Remaining)
+		}
+		if progress.Fraction() != tt.fraction {
+			t.Errorf("Test %d: Expected %v done, got %v", i+1, tt.fraction, progress.Fraction())
+		}
+	}
+}
+
+func TestProgressString(t *testing.T) {
+	tests := []struct {
+		progress Progress
+		expected string
+	}{
+		{
+			progress: newProgress(3, 6, 1234567, 1520*time.Millisecond),
+			expected: " 50% 3/6 tiles 1.2M rays 1.5s elapsed 1.5s left",
+		},
+		{
+			progress: newProgress(6, 6, 999, 2*time.Second),
+			expected: "100% 6/6 tiles 999 rays 2s elapsed 0s left",
+		},
+		{
+			progress: newProgress(1, 300, 2500, 10*time.Millisecond),
+			expected: "  0% 1/300 tiles 2.5k rays 0s elapsed 3s left",
+		},
+		{
+			progress: newProgress(0, 1, 4e9, 0),
+			expected: "  0% 0/1 tiles 4.0G rays 0s elapsed 0s left",
+		},
+	}
+	for i, tt := range tests {
+		if got := tt.progress.String(); got != tt.expected {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, tt.expected, got)
+		}
+	}
+}
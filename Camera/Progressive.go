@@ -0,0 +1,87 @@
+package Camera
+
+import (
+	"context"
+	"goRay/Ray"
+	"goRay/Vector"
+)
+
+// Progressive refines an image one sample per pixel at a time, so a viewer can show
+// a rough image right away and sharpen it while nothing changes. After Target steps
+// the image is the same as CastRays gives.
+type Progressive struct {
+	camera *Camera
+	// sum holds the radiance of all samples so far for each pixel in row order
+	sum     []Vector.Vector
+	samples int
+}
+
+func NewProgressive(camera *Camera) *Progressive {
+	return &Progressive{camera: camera}
+}
+
+// Reset throws the accumulated samples away, call it whenever the camera or scene changes
+func (p *Progressive) Reset() {
+	p.samples = 0
+}
+
+// Samples returns how many samples each pixel has
+func (p *Progressive) Samples() int {
+	return p.samples
+}
+
+// Target returns how many samples each pixel gets, the camera's anti-aliasing factor or 1 without anti-aliasing
+func (p *Progressive) Target() int {
+	return max(1, p.camera.antiAliasingFactor)
+}
+
+func (p *Progressive) Done() bool {
+	return p.samples >= p.Target()
+}
+
+// Step traces one more sample for every pixel and returns the average of all samples
+// so far. The first step after a reset also records the camera's primary rays.
+func (p *Progressive) Step() []Pixel {
+	pixels, _ := p.StepContext(context.Background())
+	return pixels
+}
+
+// StepContext is Step stopping early when ctx is cancelled, which returns the
+// context's error and keeps the samples from before the step. It must not run at the
+// same time as changes to the camera, cancel it and wait for it to return first.
+func (p *Progressive) StepContext(ctx context.Context) ([]Pixel, error) {
+	c := p.camera
+	c.prepareScene()
+	if p.samples == 0 {
+		c.primaryRays = make([]Ray.Ray, c.width*c.height)
+	}
+
+	sum := make([]Vector.Vector, c.width*c.height)
+	pixels := make([]Pixel, c.width*c.height)
+	scale := 1 / float64(p.samples+1)
+	err := c.forEachPixel(ctx, func(x, y int) {
+		i := y*c.width + x
+		if p.samples == 0 {
+			c.primaryRays[i], _ = c.getPrimaryRay(c.getPixelCenter(x, y))
+		}
+
+		if c.antiAliasingFactor > 0 {
+			var previous Vector.Vector
+			if p.samples > 0 {
+				previous = p.sum[i]
+			}
+			sum[i] = previous.Translate(c.getAntiAliasingSample(x, y, p.samples))
+		} else {
+			sum[i] = c.getCenterSample(x, y)
+		}
+		pixels[i] = newPixel(x, y, sum[i].Scale(scale))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.sum = sum
+	p.samples++
+	c.pixelList = pixels
+	return pixels, nil
+}
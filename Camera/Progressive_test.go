@@ -0,0 +1,105 @@
+package Camera
+
+import (
+	"context"
+	"errors"
+	"goRay/Light"
+	"goRay/Object"
+	"goRay/Vector"
+	"testing"
+)
+
+func TestProgressiveConvergesToCastRays(t *testing.T) {
+	tests := []struct {
+		antiAliasing int
+		steps        int
+	}{
+		{antiAliasing: 0, steps: 1},
+		{antiAliasing: 1, steps: 1},
+		{antiAliasing: 5, steps: 5},
+	}
+
+	for i, tt := range tests {
+		camera := New(16, 12, *Vector.New(0, 0, 0))
+		camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 30), *Vector.New(0.8, 0.2, 0.2), 5))
+		camera.SetLight(Light.NewDirectional(*Vector.New(-1, 1, 1), *Vector.New(1, 1, 1), 0.8))
+		camera.SetDepthOfField(0.5, 30)
+		camera.SetAntiAliasing(tt.antiAliasing)
+		expected := camera.CastRays()
+
+		progressive := NewProgressive(camera)
+		var pixels []Pixel
+		steps := 0
+		for !progressive.Done() {
+			pixels = progressive.Step()
+			steps++
+		}
+		if steps != tt.steps || progressive.Samples() != tt.steps {
+			t.Errorf("Test %d: Expected %d steps, took %d", i+1, tt.steps, steps)
+		}
+
+		for p := range expected {
+			if pixels[p] != expected[p] {
+				t.Fatalf("Test %d: Expected pixel %d to be %v, got %v", i+1, p, expected[p].Radiance(), pixels[p].Radiance())
+			}
+		}
+	}
+}
+
+func TestProgressiveReset(t *testing.T) {
+	camera := New(16, 12, *Vector.New(0, 0, 0))
+	camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 30), *Vector.New(1, 1, 1), 5))
+	camera.SetAntiAliasing(4)
+
+	progressive := NewProgressive(camera)
+	progressive.Step()
+	progressive.Step()
+
+	// moving away without a reset would average the old view into the new one
+	camera.TranslateCamera(*Vector.New(100, 0, 0))
+	progressive.Reset()
+	if progressive.Done() || progressive.Samples() != 0 {
+		t.Fatalf("Expected the reset to drop all samples, %d are left", progressive.Samples())
+	}
+
+	pixels := progressive.Step()
+	fresh := NewProgressive(camera).Step()
+	for p := range fresh {
+		if pixels[p] != fresh[p] {
+			t.Fatalf("Expected pixel %d to only hold the new view, got %v instead of %v", p, pixels[p].Radiance(), fresh[p].Radiance())
+		}
+	}
+	center := camera.GetPrimaryRays()[6*16+8]
+	if origin := center.Origin(); origin.X() != 100 {
+		t.Errorf("Expected the primary rays to start at the moved camera, got %v", origin)
+	}
+}
+
+func TestProgressiveStepCancelled(t *testing.T) {
+	camera := New(16, 12, *Vector.New(0, 0, 0))
+	camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 30), *Vector.New(1, 1, 1), 5))
+	camera.SetAntiAliasing(4)
+
+	progressive := NewProgressive(camera)
+	progressive.Step()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := progressive.StepContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancelled step to fail with %v, got %v", context.Canceled, err)
+	}
+	if progressive.Samples() != 1 {
+		t.Fatalf("Expected the cancelled step not to count, got %d samples", progressive.Samples())
+	}
+
+	// the next step carries on from the samples before the cancelled one
+	pixels := progressive.Step()
+	expected := NewProgressive(camera)
+	expected.Step()
+	expectedPixels := expected.Step()
+	for p := range pixels {
+		if pixels[p] != expectedPixels[p] {
+			t.Fatalf("Expected pixel %d to be %v, got %v", p, expectedPixels[p].Radiance(), pixels[p].Radiance())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package Camera
+
+import (
+	"goRay/Vector"
+	"math"
+)
+
+// Projection maps a point on the image to a camera space ray, where the camera sits
+// at the origin looking along +z. x and y are measured in pixels from the center of
+// a width by height image, y pointing down. ok is false where the image shows nothing.
+type Projection interface {
+	Project(x, y float64, width, height int) (origin, direction Vector.Vector, ok bool)
+}
+
+// Perspective is a pinhole camera. Without a field of view the original projection
+// zoomed by the image height is used.
+type Perspective struct {
+	// VerticalFieldOfView in radians
+	VerticalFieldOfView float64
+}
+
+func (p Perspective) Project(x, y float64, width, height int) (Vector.Vector, Vector.Vector, bool) {
+	if p.VerticalFieldOfView == 0 {
+		return Vector.Vector{}, *GetPixelHeadingVector(y, x, float64(height)), true
+	}
+
+	// size of a pixel on a plane one unit in front of the camera
+	pixelSize := 2 * math.Tan(p.VerticalFieldOfView/2) / float64(height)
+	return Vector.Vector{}, Vector.New(x*pixelSize, y*pixelSize, 1).Normalize(), true
+}
+
+// Orthographic sends parallel rays from a rectangle Height world units tall, so
+// objects keep their size however far away they are
+type Orthographic struct {
+	Height float64
+}
+
+func (o Orthographic) Project(x, y float64, width, height int) (Vector.Vector, Vector.Vector, bool) {
+	scale := o.Height / float64(height)
+	return *Vector.New(x*scale, y*scale, 0), *Vector.New(0, 0, 1), true
+}
+
+// Fisheye is an equidistant fisheye, the angle from the view direction grows evenly
+// with the distance from the image center. FieldOfView in radians spans the largest
+// circle fitting the image, pixels outside it show nothing.
+type Fisheye struct {
+	FieldOfView float64
+}
+
+func (f Fisheye) Project(x, y float64, width, height int) (Vector.Vector, Vector.Vector, bool) {
+	radius := math.Min(float64(width), float64(height)) / 2
+	distance := math.Hypot(x, y) / radius
+	if distance > 1 {
+		return Vector.Vector{}, Vector.Vector{}, false
+	}
+
+	theta := distance * f.FieldOfView / 2
+	phi := math.Atan2(y, x)
+	return Vector.Vector{}, *Vector.New(math.Sin(theta)*math.Cos(phi), math.Sin(theta)*math.Sin(phi), math.Cos(theta)), true
+}
+
+// Equirectangular is a full 360 by 180 degree panorama, longitude runs across the
+// image with the view direction in the middle and latitude runs down it
+type Equirectangular struct{}
+
+func (Equirectangular) Project(x, y float64, width, height int) (Vector.Vector, Vector.Vector, bool) {
+	longitude := x / float64(width) * 2 * math.Pi
+	latitude := y / float64(height) * math.Pi
+	return Vector.Vector{}, *Vector.New(math.Cos(latitude)*math.Sin(longitude), math.Sin(latitude), math.Cos(latitude)*math.Cos(longitude)), true
+}
@@ -0,0 +1,148 @@
+package Camera
+
+import (
+	"goRay/Object"
+	"goRay/Vector"
+	color2 "image/color"
+	"math"
+	"testing"
+)
+
+func TestPerspectiveWithoutFieldOfViewMatchesHeadingVector(t *testing.T) {
+	for i, point := range [][2]float64{{0, 0}, {-49.5, 12.5}, {3.5, -20.5}} {
+		origin, direction, ok := Perspective{}.Project(point[0], point[1], 100, 50)
+		want := GetPixelHeadingVector(point[1], point[0], 50)
+		if !ok || origin != (Vector.Vector{}) || direction != *want {
+			t.Errorf("Test %d: Expected a ray from the origin along %v, got %v from %v", i+1, want, direction, origin)
+		}
+	}
+}
+
+func TestOrthographic(t *testing.T) {
+	tests := []struct {
+		x, y   float64
+		origin Vector.Vector
+	}{
+		{x: 0, y: 0, origin: *Vector.New(0, 0, 0)},
+		{x: -49.5, y: -24.5, origin: *Vector.New(-9.9, -4.9, 0)},
+		{x: 50, y: 25, origin: *Vector.New(10, 5, 0)},
+	}
+	for i, tt := range tests {
+		origin, direction, ok := Orthographic{Height: 10}.Project(tt.x, tt.y, 100, 50)
+		if !ok || origin.DistanceBetween(tt.origin) > 1e-9 || direction != *Vector.New(0, 0, 1) {
+			t.Errorf("Test %d: Expected a ray from %v along +z, got %v from %v", i+1, tt.origin, direction, origin)
+		}
+	}
+}
+
+func TestFisheye(t *testing.T) {
+	tests := []struct {
+		fieldOfView float64
+		x, y        float64
+		ok          bool
+		direction   Vector.Vector
+	}{
+		{fieldOfView: math.Pi, x: 0, y: 0, ok: true, direction: *Vector.New(0, 0, 1)},
+		{fieldOfView: math.Pi, x: 25, y: 0, ok: true, direction: *Vector.New(1, 0, 0)},
+		{fieldOfView: math.Pi, x: 0, y: -25, ok: true, direction: *Vector.New(0, -1, 0)},
+		{fieldOfView: math.Pi / 2, x: 12.5, y: 0, ok: true, direction: *Vector.New(math.Sin(math.Pi/8), 0, math.Cos(math.Pi/8))},
+		{fieldOfView: 2 * math.Pi, x: -25, y: 0, ok: true, direction: *Vector.New(0, 0, -1)},
+		{fieldOfView: math.Pi, x: 20, y: 20, ok: false},
+		{fieldOfView: math.Pi, x: 49, y: 0, ok: false},
+	}
+	for i, tt := range tests {
+		_, direction, ok := Fisheye{FieldOfView: tt.fieldOfView}.Project(tt.x, tt.y, 100, 50)
+		if ok != tt.ok {
+			t.Errorf("Test %d: Expected ok to be %t", i+1, tt.ok)
+			continue
+		}
+		if ok && direction.DistanceBetween(tt.direction) > 1e-9 {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, tt.direction, direction)
+		}
+	}
+}
+
+func TestEquirectangular(t *testing.T) {
+	tests := []struct {
+		x, y      float64
+		direction Vector.Vector
+	}{
+		{x: 0, y: 0, direction: *Vector.New(0, 0, 1)},
+		{x: 50, y: 0, direction: *Vector.New(1, 0, 0)},
+		{x: -50, y: 0, direction: *Vector.New(-1, 0, 0)},
+		{x: 100, y: 0, direction: *Vector.New(0, 0, -1)},
+		{x: 0, y: -50, direction: *Vector.New(0, -1, 0)},
+		{x: 0, y: 25, direction: *Vector.New(0, math.Sqrt(0.5), math.Sqrt(0.5))},
+	}
+	for i, tt := range tests {
+		_, direction, ok := Equirectangular{}.Project(tt.x, tt.y, 200, 100)
+		if !ok || direction.DistanceBetween(tt.direction) > 1e-9 {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, tt.direction, direction)
+		}
+	}
+}
+
+func TestProjectionsRender(t *testing.T) {
+	black := color2.RGBA{A: 255}
+	red := *Vector.New(1, 0, 0)
+
+	tests := []struct {
+		name       string
+		projection Projection
+		sphere     *Object.Sphere
+		x, y       int
+		hits       bool
+	}{
+		{
+			name:       "orthographic keeps offset objects off center",
+			projection: Orthographic{Height: 10},
+			sphere:     Object.NewSphere(*Vector.New(4, 0, 50), red, 1),
+			x:          8,
+			y:          5,
+			hits:       true,
+		},
+		{
+			name:       "orthographic misses beside a sphere",
+			projection: Orthographic{Height: 10},
+			sphere:     Object.NewSphere(*Vector.New(4, 0, 50), red, 1),
+			x:          5,
+			y:          5,
+			hits:       false,
+		},
+		{
+			name:       "panorama sees behind the camera",
+			projection: Equirectangular{},
+			sphere:     Object.NewSphere(*Vector.New(0, 0, -50), red, 20),
+			x:          0,
+			y:          5,
+			hits:       true,
+		},
+		{
+			name:       "fisheye sees beside the camera",
+			projection: Fisheye{FieldOfView: math.Pi},
+			sphere:     Object.NewSphere(*Vector.New(50, 0, 0), red, 10),
+			x:          9,
+			y:          5,
+			hits:       true,
+		},
+	}
+	for i, tt := range tests {
+		camera := New(10, 10, *Vector.New(0, 0, 0))
+		camera.SetProjection(tt.projection)
+		background := New(10, 10, *Vector.New(0, 0, 0))
+		background.SetProjection(tt.projection)
+		camera.SetObject(tt.sphere)
+
+		got := camera.CastRays()[tt.y*10+tt.x].Color()
+		want := background.CastRays()[tt.y*10+tt.x].Color()
+		if tt.hits == (got == want) {
+			t.Errorf("Test %d: %s: Expected intersection to be '%t'", i+1, tt.name, tt.hits)
+		}
+	}
+
+	camera := New(10, 10, *Vector.New(0, 0, 0))
+	camera.SetProjection(Fisheye{FieldOfView: math.Pi})
+	if corner := camera.CastRays()[0].Color(); corner != black {
+		t.Errorf("Expected black outside the fisheye circle, got %v", corner)
+	}
+}
@@ -0,0 +1,88 @@
+package Camera
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tileSize is the side of the square blocks of pixels the workers pull, small enough
+// that expensive parts of the image spread over all workers
+const tileSize = 16
+
+type tile struct {
+	xStart, yStart, xEnd, yEnd int
+}
+
+// getTiles covers the image with tiles in row order, tiles on the right and bottom
+// edges are cut to the image
+func (c *Camera) getTiles() []tile {
+	var tiles []tile
+	for yStart := 0; yStart < c.height; yStart += tileSize {
+		for xStart := 0; xStart < c.width; xStart += tileSize {
+			tiles = append(tiles, tile{
+				xStart: xStart,
+				yStart: yStart,
+				xEnd:   min(xStart+tileSize, c.width),
+				yEnd:   min(yStart+tileSize, c.height),
+			})
+		}
+	}
+	return tiles
+}
+
+// forEachPixel calls shade for every pixel from a pool of GOMAXPROCS workers pulling
+// tiles from a queue, reporting each finished tile to the progress observer. Once ctx
+// is done no further tiles are started and its error is returned, tiles already
+// started are finished.
+func (c *Camera) forEachPixel(ctx context.Context, shade func(x, y int)) error {
+	all := c.getTiles()
+	start := time.Now()
+	startRays := atomic.LoadInt64(&c.rays)
+	var progressLock sync.Mutex
+	tilesDone := 0
+	reportTile := func() {
+		if c.progressObserver == nil {
+			return
+		}
+		progressLock.Lock()
+		defer progressLock.Unlock()
+		tilesDone++
+		c.progressObserver(newProgress(tilesDone, len(all), atomic.LoadInt64(&c.rays)-startRays, time.Since(start)))
+	}
+
+	tiles := make(chan tile)
+	var wg sync.WaitGroup
+	for worker := 0; worker < runtime.GOMAXPROCS(0); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range tiles {
+				for y := t.yStart; y < t.yEnd; y++ {
+					for x := t.xStart; x < t.xEnd; x++ {
+						shade(x, y)
+					}
+				}
+				reportTile()
+			}
+		}()
+	}
+
+	sent := 0
+	for sent < len(all) && ctx.Err() == nil {
+		select {
+		case tiles <- all[sent]:
+			sent++
+		case <-ctx.Done():
+		}
+	}
+	close(tiles)
+	wg.Wait()
+
+	if sent < len(all) {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package Camera
+
+import (
+	"context"
+	"errors"
+	"goRay/Light"
+	"goRay/Object"
+	"goRay/Vector"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCastRaysConcurrentCoversEveryPixel(t *testing.T) {
+	tests := []struct {
+		width, height int
+	}{
+		{width: 101, height: 77},
+		{width: 1, height: 1},
+		{width: 17, height: 3},
+		{width: 3, height: 40},
+		{width: 64, height: 32},
+	}
+
+	for i, tt := range tests {
+		camera := New(tt.width, tt.height, *Vector.New(0, 0, 0))
+		camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 30), *Vector.New(0.8, 0.2, 0.2), 10))
+		camera.SetLight(Light.NewDirectional(*Vector.New(-1, 1, 1), *Vector.New(1, 1, 1), 0.8))
+		expected := camera.CastRays()
+		expectedRays := camera.GetPrimaryRays()
+
+		pixels := camera.CastRaysConcurrent()
+		if len(pixels) != tt.width*tt.height {
+			t.Fatalf("Test %d: Expected %d pixels, got %d", i+1, tt.width*tt.height, len(pixels))
+		}
+		for p, pixel := range pixels {
+			if pixel.X() != p%tt.width || pixel.Y() != p/tt.width {
+				t.Fatalf("Test %d: Expected pixel %d at %d,%d, got %d,%d", i+1, p, p%tt.width, p/tt.width, pixel.X(), pixel.Y())
+			}
+			if pixel != expected[p] {
+				t.Fatalf("Test %d: Expected pixel %d,%d to be %v, got %v", i+1, pixel.X(), pixel.Y(), expected[p].Radiance(), pixel.Radiance())
+			}
+			if ray := camera.GetPrimaryRays()[p]; ray.Direction() != expectedRays[p].Direction() {
+				t.Fatalf("Test %d: Expected primary ray %d to be recorded", i+1, p)
+			}
+		}
+	}
+}
+
+func TestGetTiles(t *testing.T) {
+	camera := New(40, 17, *Vector.New(0, 0, 0))
+	covered := make([]int, 40*17)
+	for _, tile := range camera.getTiles() {
+		if tile.xEnd-tile.xStart > tileSize || tile.yEnd-tile.yStart > tileSize {
+			t.Errorf("Expected tiles of at most %d pixels a side, got %v", tileSize, tile)
+		}
+		for y := tile.yStart; y < tile.yEnd; y++ {
+			for x := tile.xStart; x < tile.xEnd; x++ {
+				covered[y*40+x]++
+			}
+		}
+	}
+	for p, count := range covered {
+		if count != 1 {
+			t.Fatalf("Expected pixel %d,%d to be in exactly one tile, it is in %d", p%40, p/40, count)
+		}
+	}
+}
+
+func TestCastRaysContextCancelled(t *testing.T) {
+	camera := New(32, 32, *Vector.New(0, 0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := camera.CastRaysContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled cast to fail with %v, got %v", context.Canceled, err)
+	}
+	if _, err := camera.RenderContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled render to fail with %v, got %v", context.Canceled, err)
+	}
+	if _, err := camera.CastRaysContext(context.Background()); err != nil {
+		t.Errorf("Expected an uncancelled cast to succeed, got %v", err)
+	}
+}
+
+func TestForEachPixelStopsWhenCancelled(t *testing.T) {
+	camera := New(512, 512, *Vector.New(0, 0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var shaded atomic.Int64
+	err := camera.forEachPixel(ctx, func(x, y int) {
+		shaded.Add(1)
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if shaded.Load() >= 512*512 {
+		t.Errorf("Expected the cancel to stop the render early, all %d pixels were shaded", shaded.Load())
+	}
+}
@@ -0,0 +1,117 @@
+package DebugView
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Canvas is a 2D surface the debug views draw on, coordinates are in pixels. Colors
+// that aren't opaque are blended over what is already drawn.
+type Canvas interface {
+	SetColor(c color.NRGBA)
+	DrawPoint(x, y float64)
+	DrawLine(x1, y1, x2, y2 float64)
+}
+
+// ImageCanvas draws into an in-memory image, so debug views can be saved without a window
+type ImageCanvas struct {
+	img   *image.RGBA
+	color color.NRGBA
+}
+
+// NewImageCanvas returns a black canvas of the given size drawing in white
+func NewImageCanvas(width, height int) *ImageCanvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+	return &ImageCanvas{img: img, color: color.NRGBA{R: 255, G: 255, B: 255, A: 255}}
+}
+
+func (c *ImageCanvas) Image() *image.RGBA {
+	return c.img
+}
+
+func (c *ImageCanvas) SetColor(col color.NRGBA) {
+	c.color = col
+}
+
+// DrawPoint blends the color into the pixel the point falls in, points outside the
+// image are dropped
+func (c *ImageCanvas) DrawPoint(x, y float64) {
+	c.drawPixel(int(math.Floor(x)), int(math.Floor(y)))
+}
+
+func (c *ImageCanvas) drawPixel(x, y int) {
+	if !(image.Point{X: x, Y: y}).In(c.img.Bounds()) {
+		return
+	}
+	alpha := uint32(c.color.A)
+	blend := func(src uint8, dst uint8) uint8 {
+		return uint8((uint32(src)*alpha + uint32(dst)*(255-alpha) + 127) / 255)
+	}
+	under := c.img.RGBAAt(x, y)
+	c.img.SetRGBA(x, y, color.RGBA{
+		R: blend(c.color.R, under.R),
+		G: blend(c.color.G, under.G),
+		B: blend(c.color.B, under.B),
+		A: blend(255, under.A),
+	})
+}
+
+// DrawLine steps along the longer axis one pixel at a time, the part of the line outside the image is dropped
+func (c *ImageCanvas) DrawLine(x1, y1, x2, y2 float64) {
+	x1, y1, x2, y2, ok := c.clip(x1, y1, x2, y2)
+	if !ok {
+		return
+	}
+
+	steps := math.Ceil(math.Max(math.Abs(x2-x1), math.Abs(y2-y1)))
+	if steps == 0 {
+		c.DrawPoint(x1, y1)
+		return
+	}
+	// consecutive steps can fall in the same pixel, it is only blended once
+	last := image.Point{X: math.MinInt, Y: math.MinInt}
+	for i := 0.0; i <= steps; i++ {
+		t := i / steps
+		pixel := image.Point{X: int(math.Floor(x1 + (x2-x1)*t)), Y: int(math.Floor(y1 + (y2-y1)*t))}
+		if pixel != last {
+			c.drawPixel(pixel.X, pixel.Y)
+			last = pixel
+		}
+	}
+}
+
+// clip cuts the line to the image bounds with the Liang-Barsky algorithm, so long
+// lines reaching far outside don't cost a step per pixel they span
+func (c *ImageCanvas) clip(x1, y1, x2, y2 float64) (float64, float64, float64, float64, bool) {
+	bounds := c.img.Bounds()
+	dx, dy := x2-x1, y2-y1
+	tMin, tMax := 0.0, 1.0
+	edges := []struct{ p, q float64 }{
+		{-dx, x1 - float64(bounds.Min.X)},
+		{dx, float64(bounds.Max.X) - x1},
+		{-dy, y1 - float64(bounds.Min.Y)},
+		{dy, float64(bounds.Max.Y) - y1},
+	}
+	for _, edge := range edges {
+		if edge.p == 0 {
+			if edge.q < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+		t := edge.q / edge.p
+		if edge.p < 0 {
+			tMin = math.Max(tMin, t)
+		} else {
+			tMax = math.Min(tMax, t)
+		}
+	}
+	if tMin > tMax {
+		return 0, 0, 0, 0, false
+	}
+	return x1 + dx*tMin, y1 + dy*tMin, x1 + dx*tMax, y1 + dy*tMax, true
+}
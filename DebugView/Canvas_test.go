@@ -0,0 +1,76 @@
+package DebugView
+
+import (
+	"image/color"
+	"testing"
+)
+
+var white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+func TestImageCanvasDrawLine(t *testing.T) {
+	tests := []struct {
+		x1, y1, x2, y2 float64
+		drawn          [][2]int
+		count          int
+	}{
+		// horizontal, vertical and diagonal lines cover every pixel between their ends
+		{x1: 1, y1: 1, x2: 6, y2: 1, drawn: [][2]int{{1, 1}, {3, 1}, {6, 1}}, count: 6},
+		{x1: 2, y1: 7, x2: 2, y2: 0, drawn: [][2]int{{2, 0}, {2, 4}, {2, 7}}, count: 8},
+		{x1: 0, y1: 0, x2: 7, y2: 7, drawn: [][2]int{{0, 0}, {4, 4}, {7, 7}}, count: 8},
+		{x1: 3, y1: 3, x2: 3, y2: 3, drawn: [][2]int{{3, 3}}, count: 1},
+		// lines reaching outside are cut to the image
+		{x1: -1000, y1: 2, x2: 1000, y2: 2, drawn: [][2]int{{0, 2}, {7, 2}}, count: 8},
+		{x1: 4, y1: 4, x2: 4, y2: 1e9, drawn: [][2]int{{4, 4}, {4, 7}}, count: 4},
+		// lines that miss the image draw nothing
+		{x1: -5, y1: -5, x2: -1, y2: 20, count: 0},
+		{x1: 20, y1: 1, x2: 30, y2: 1, count: 0},
+	}
+
+	for i, tt := range tests {
+		canvas := NewImageCanvas(8, 8)
+		canvas.DrawLine(tt.x1, tt.y1, tt.x2, tt.y2)
+		img := canvas.Image()
+
+		for _, point := range tt.drawn {
+			if got := img.RGBAAt(point[0], point[1]); got != white {
+				t.Errorf("Test %d: Expected %v to be drawn, got %v", i+1, point, got)
+			}
+		}
+		count := 0
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				if img.RGBAAt(x, y) == white {
+					count++
+				}
+			}
+		}
+		if count != tt.count {
+			t.Errorf("Test %d: Expected %d pixels to be drawn, got %d", i+1, tt.count, count)
+		}
+	}
+}
+
+func TestImageCanvasColor(t *testing.T) {
+	canvas := NewImageCanvas(4, 4)
+	if got := canvas.Image().RGBAAt(1, 1); got != (color.RGBA{A: 255}) {
+		t.Errorf("Expected an opaque black canvas, got %v", got)
+	}
+
+	canvas.SetColor(color.NRGBA{R: 255, A: 255})
+	canvas.DrawPoint(2.7, 1.2)
+	canvas.DrawPoint(-1, 10)
+	if got := canvas.Image().RGBAAt(2, 1); got != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("Expected the point to be drawn in red, got %v", got)
+	}
+
+	// a fifth of the way from red to white
+	canvas.SetColor(color.NRGBA{R: 255, G: 255, B: 255, A: 51})
+	canvas.DrawPoint(2, 1)
+	if got := canvas.Image().RGBAAt(2, 1); got != (color.RGBA{R: 255, G: 51, B: 51, A: 255}) {
+		t.Errorf("Expected the translucent point to be blended over red, got %v", got)
+	}
+	canvas.DrawLine(0.2, 3, 1.7, 3)
+	if got := canvas.Image().RGBAAt(0, 3); got != (color.RGBA{R: 51, G: 51, B: 51, A: 255}) {
+		t.Errorf("Expected each pixel of a translucent line to be blended once, got %v", got)
+	}
+}
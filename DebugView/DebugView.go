@@ -0,0 +1,182 @@
+package DebugView
+
+import (
+	"goRay/Camera"
+	"goRay/Object"
+	"goRay/Vector"
+	"image"
+	"image/color"
+)
+
+// offsets move the world origin into view, the top down view of x and z is drawn
+// at topDownOffset and the side view of x and y at sideOffset
+const (
+	topDownOffset = 100
+	sideOffset    = 30
+	// objectOffset places objects in the same frame as the top down rays
+	objectOffset = 100
+)
+
+// planeReach is how far planes are drawn either side of their point
+const planeReach = 1000
+
+// rays, hits and the camera are translucent so the image shows through where they cross it
+var (
+	rayColor    = color.NRGBA{R: 200, G: 100, B: 200, A: 50}
+	hitColor    = color.NRGBA{G: 255, A: 50}
+	objectColor = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	cameraColor = color.NRGBA{R: 200, G: 255, B: 100, A: 50}
+)
+
+// MiniMap draws the overlay of the camera's last cast onto a black image
+func MiniMap(camera *Camera.Camera, width, height int) *image.RGBA {
+	canvas := NewImageCanvas(width, height)
+	DrawOverlay(canvas, camera)
+	return canvas.Image()
+}
+
+// DrawOverlay draws the primary rays of the camera's last cast from above and from
+// the side, and the outlines of the objects from above
+func DrawOverlay(canvas Canvas, camera *Camera.Camera) {
+	DrawPrimaryRays(canvas, camera)
+	DrawVerticalPrimaryRays(canvas, camera)
+	DrawObjects(canvas, camera.ObjectList)
+}
+
+func DrawObjects(canvas Canvas, objectList []Object.Object) {
+	canvas.SetColor(objectColor)
+	for _, object := range objectList {
+		DrawObject(canvas, object, objectOffset, objectOffset)
+	}
+}
+
+// DrawObject outlines the footprint of an object seen from above. Planes show where
+// they cut the ground, planes facing straight up or down cover the whole map so they
+// aren't drawn.
+func DrawObject(canvas Canvas, object Object.Object, xOffset, yOffset float64) {
+	switch o := object.(type) {
+	case *Object.Sphere:
+		center := o.Center()
+		drawCircle(canvas, int(center.X()+xOffset), int(center.Z()+yOffset), o.Radius())
+	case *Object.Triangle:
+		v0, v1, v2 := o.Vertices()
+		canvas.DrawLine(v0.X()+xOffset, v0.Z()+yOffset, v1.X()+xOffset, v1.Z()+yOffset)
+		canvas.DrawLine(v1.X()+xOffset, v1.Z()+yOffset, v2.X()+xOffset, v2.Z()+yOffset)
+		canvas.DrawLine(v2.X()+xOffset, v2.Z()+yOffset, v0.X()+xOffset, v0.Z()+yOffset)
+	case *Object.AABox:
+		lower, upper := o.Min(), o.Max()
+		x1, z1 := lower.X()+xOffset, lower.Z()+yOffset
+		x2, z2 := upper.X()+xOffset, upper.Z()+yOffset
+		canvas.DrawLine(x1, z1, x2, z1)
+		canvas.DrawLine(x2, z1, x2, z2)
+		canvas.DrawLine(x2, z2, x1, z2)
+		canvas.DrawLine(x1, z2, x1, z1)
+	case *Object.Plane:
+		normal, point := o.Normal(), o.Point()
+		along := Vector.New(-normal.Z(), 0, normal.X())
+		if along.X() == 0 && along.Z() == 0 {
+			return
+		}
+		reach := along.Normalize().Scale(planeReach)
+		x, z := point.X()+xOffset, point.Z()+yOffset
+		canvas.DrawLine(x-reach.X(), z-reach.Z(), x+reach.X(), z+reach.Z())
+	case Object.Aggregate:
+		for _, primitive := range o.Primitives() {
+			DrawObject(canvas, primitive, xOffset, yOffset)
+		}
+	}
+}
+
+// DrawRays draws the heading of every pixel from the camera position, seen from above
+func DrawRays(canvas Canvas, camera *Camera.Camera) {
+	canvas.SetColor(rayColor)
+	camx, camz := camera.CameraPosition.X(), camera.CameraPosition.Z()
+	for _, vectorList := range camera.ScreenCellMatrix {
+		for _, vector := range vectorList {
+			heading := camera.Orient(*vector)
+			end := heading.Translate(camera.CameraPosition)
+			drawLine(canvas, camx, camz, end.X(), end.Z(), topDownOffset)
+		}
+	}
+}
+
+// DrawPrimaryRays draws the primary rays of the camera's last cast seen from above
+func DrawPrimaryRays(canvas Canvas, camera *Camera.Camera) {
+	canvas.SetColor(rayColor)
+	for _, ray := range camera.GetPrimaryRays() {
+		startPoint := ray.Origin()
+		endPoint := startPoint.Translate(ray.Direction().Scale(30))
+		drawLine(canvas, startPoint.X(), startPoint.Z(), endPoint.X(), endPoint.Z(), topDownOffset)
+	}
+}
+
+// DrawVerticalPrimaryRays draws the primary rays of the camera's last cast seen from
+// the side, rays hitting an object are highlighted
+func DrawVerticalPrimaryRays(canvas Canvas, camera *Camera.Camera) {
+	for _, ray := range camera.GetPrimaryRays() {
+		hits := false
+		for _, obj := range camera.ObjectList {
+			if intersects, _ := obj.IntersectDistance(ray); intersects {
+				hits = true
+				break
+			}
+		}
+
+		startPoint := ray.Origin()
+		endPoint := startPoint.Translate(ray.Direction().Scale(40))
+		if hits {
+			canvas.SetColor(hitColor)
+		} else {
+			canvas.SetColor(rayColor)
+		}
+		drawLine(canvas, startPoint.X(), startPoint.Y(), endPoint.X(), endPoint.Y(), sideOffset)
+	}
+}
+
+func DrawRotationLine(canvas Canvas, camera *Camera.Camera) {
+	canvas.SetColor(rayColor)
+	x1, y1, x2, y2 := camera.GetRotationLine()
+	drawLine(canvas, float64(x1), float64(y1), float64(x2), float64(y2), 50)
+}
+
+// DrawCameraPosition draws a line from the world origin to the camera seen from above
+func DrawCameraPosition(canvas Canvas, camera *Camera.Camera) {
+	canvas.SetColor(cameraColor)
+	x, z := camera.CameraPosition.X(), camera.CameraPosition.Z()
+	canvas.DrawLine(topDownOffset, topDownOffset, x*-30+topDownOffset, z*-30+topDownOffset)
+}
+
+func drawLine(canvas Canvas, x1, y1, x2, y2, offset float64) {
+	canvas.DrawLine(x1+offset, y1+offset, x2+offset, y2+offset)
+}
+
+// drawCircle draws the outline of a circle with the midpoint algorithm
+func drawCircle(canvas Canvas, centreX, centreY, radius int) {
+	x := radius - 1
+	y := 0
+	tx := 1
+	ty := 1
+	error := tx - radius*2
+
+	for x >= y {
+		//  Each of the following renders an octant of the circle
+		for _, point := range [][2]int{
+			{centreX + x, centreY - y}, {centreX + x, centreY + y}, {centreX - x, centreY - y}, {centreX - x, centreY + y},
+			{centreX + y, centreY - x}, {centreX + y, centreY + x}, {centreX - y, centreY - x}, {centreX - y, centreY + x},
+		} {
+			canvas.DrawPoint(float64(point[0]), float64(point[1]))
+		}
+
+		if error <= 0 {
+			y++
+			error += ty
+			ty += 2
+		}
+
+		if error > 0 {
+			x--
+			tx += 2
+			error += tx - radius*2
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package DebugView
+
+import (
+	"goRay/Camera"
+	"goRay/Object"
+	"goRay/Vector"
+	"image/color"
+	"testing"
+)
+
+// recordingCanvas keeps what was drawn instead of drawing it
+type recordingCanvas struct {
+	lines  [][4]float64
+	points [][2]float64
+}
+
+func (c *recordingCanvas) SetColor(color.NRGBA) {}
+
+func (c *recordingCanvas) DrawPoint(x, y float64) {
+	c.points = append(c.points, [2]float64{x, y})
+}
+
+func (c *recordingCanvas) DrawLine(x1, y1, x2, y2 float64) {
+	c.lines = append(c.lines, [4]float64{x1, y1, x2, y2})
+}
+
+func TestDrawObject(t *testing.T) {
+	white := *Vector.New(1, 1, 1)
+	triangle := Object.NewTriangle(*Vector.New(0, 5, 0), *Vector.New(10, 5, 0), *Vector.New(0, 5, 10), white)
+	tests := []struct {
+		object Object.Object
+		lines  [][4]float64
+		points int
+	}{
+		{
+			object: triangle,
+			lines:  [][4]float64{{100, 100, 110, 100}, {110, 100, 100, 110}, {100, 110, 100, 100}},
+		},
+		{
+			object: Object.NewMesh([]*Object.Triangle{triangle, triangle}, white),
+			lines: [][4]float64{
+				{100, 100, 110, 100}, {110, 100, 100, 110}, {100, 110, 100, 100},
+				{100, 100, 110, 100}, {110, 100, 100, 110}, {100, 110, 100, 100},
+			},
+		},
+		{
+			object: Object.NewAABox(*Vector.New(-2, 0, 4), *Vector.New(3, 1, 9), white),
+			lines:  [][4]float64{{98, 104, 103, 104}, {103, 104, 103, 109}, {103, 109, 98, 109}, {98, 109, 98, 104}},
+		},
+		{
+			object: Object.NewPlane(*Vector.New(0, 0, 20), *Vector.New(0, 0, -1), white),
+			lines:  [][4]float64{{-900, 120, 1100, 120}},
+		},
+		{
+			// the ground covers the whole map
+			object: Object.NewPlane(*Vector.New(0, 10, 0), *Vector.New(0, -1, 0), white),
+		},
+		{
+			object: Object.NewSphere(*Vector.New(10, 0, 10), white, 5),
+			points: 8 * 4,
+		},
+	}
+
+	for i, tt := range tests {
+		canvas := &recordingCanvas{}
+		DrawObject(canvas, tt.object, 100, 100)
+		if len(canvas.lines) != len(tt.lines) {
+			t.Fatalf("Test %d: Expected %d lines, got %v", i+1, len(tt.lines), canvas.lines)
+		}
+		for l, line := range tt.lines {
+			if canvas.lines[l] != line {
+				t.Errorf("Test %d: Expected line %d to be %v, got %v", i+1, l, line, canvas.lines[l])
+			}
+		}
+		if len(canvas.points) != tt.points {
+			t.Errorf("Test %d: Expected %d points, got %d", i+1, tt.points, len(canvas.points))
+		}
+		for _, point := range canvas.points {
+			if dx, dy := point[0]-110, point[1]-110; dx*dx+dy*dy > 25 || dx*dx+dy*dy < 9 {
+				t.Errorf("Test %d: Expected %v on the outline of the sphere", i+1, point)
+			}
+		}
+	}
+}
+
+func TestMiniMap(t *testing.T) {
+	camera := Camera.New(8, 6, *Vector.New(0, 0, 0))
+	camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 60), *Vector.New(1, 0, 0), 40))
+	camera.CastRaysConcurrent()
+
+	img := MiniMap(camera, 200, 200)
+	// rays and hits are translucent, overlapping ones build up the same hue
+	hue := func(c color.RGBA) [3]bool {
+		return [3]bool{c.R > c.G, c.G > c.B, c.B > c.R}
+	}
+	tests := []struct {
+		x, y  int
+		color color.NRGBA
+		exact bool
+	}{
+		// the camera at the origin looks down the z axis of the top down view
+		{x: 100, y: 110, color: rayColor},
+		// in the side view every ray hits the sphere
+		{x: 30, y: 30, color: hitColor},
+		// the sphere outline
+		{x: 100, y: 199, color: objectColor, exact: true},
+		{x: 60, y: 160, color: color.NRGBA{A: 255}, exact: true},
+	}
+	for i, tt := range tests {
+		got := img.RGBAAt(tt.x, tt.y)
+		want := color.RGBAModel.Convert(color.NRGBA{R: tt.color.R, G: tt.color.G, B: tt.color.B, A: 255}).(color.RGBA)
+		if tt.exact && got != want || !tt.exact && (got == (color.RGBA{A: 255}) || hue(got) != hue(want)) {
+			t.Errorf("Test %d: Expected %v at %d,%d, got %v", i+1, tt.color, tt.x, tt.y, got)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package Environment
+
+import (
+	"fmt"
+	"goRay/Film"
+	"goRay/Output"
+	"goRay/Vector"
+	"math"
+	"os"
+	"slices"
+)
+
+// Map is an equirectangular environment image surrounding the scene, the top row
+// straight up (-y), the bottom row straight down and the middle column looking along
+// +z, +x a quarter of the way further right. It is seen by rays leaving the scene and
+// also lights it, shadow rays being sent towards the bright parts more often.
+type Map struct {
+	film      *Film.Film
+	rotation  float64
+	intensity float64
+	// rowCDF holds the running sum of the rows' weights, columnCDF the running sums
+	// within every row, each starting at 0 and normalized to end at 1
+	rowCDF    []float64
+	columnCDF [][]float64
+	// total is the sum of all pixel weights
+	total float64
+}
+
+// New builds the sampling tables of the film, which holds linear radiance
+func New(film *Film.Film) *Map {
+	width, height := film.Width(), film.Height()
+	m := &Map{
+		film:      film,
+		intensity: 1,
+		rowCDF:    make([]float64, height+1),
+		columnCDF: make([][]float64, height),
+	}
+
+	for y := 0; y < height; y++ {
+		// rows near the poles cover less of the sphere
+		sinTheta := math.Sin(math.Pi * (float64(y) + 0.5) / float64(height))
+		m.columnCDF[y] = make([]float64, width+1)
+		for x := 0; x < width; x++ {
+			m.columnCDF[y][x+1] = m.columnCDF[y][x] + m.weight(x, y, sinTheta)
+		}
+		rowWeight := m.columnCDF[y][width]
+		m.rowCDF[y+1] = m.rowCDF[y] + rowWeight
+		normalize(m.columnCDF[y])
+	}
+	m.total = m.rowCDF[height]
+	normalize(m.rowCDF)
+	return m
+}
+
+// Load reads an equirectangular Radiance .hdr image
+func Load(path string) (*Map, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	film, err := Output.DecodeHDR(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return New(film), nil
+}
+
+// SetRotation turns the map around the vertical axis by radians, from +z towards +x
+func (m *Map) SetRotation(radians float64) {
+	m.rotation = radians
+}
+
+// SetIntensity scales the light of the map, it starts at 1
+func (m *Map) SetIntensity(intensity float64) {
+	m.intensity = intensity
+}
+
+// Radiance returns the light arriving from direction, the pixel it falls in scaled
+// by the intensity
+func (m *Map) Radiance(direction Vector.Vector) Vector.Vector {
+	x, y := m.pixel(direction.Normalize())
+	r, g, b := m.film.At(x, y)
+	return Vector.New(float64(r), float64(g), float64(b)).Scale(m.intensity)
+}
+
+// Area is the unit sphere of directions the map covers, which makes the camera spread
+// several shadow rays over it
+func (m *Map) Area() float64 {
+	return 4 * math.Pi
+}
+
+// Sample picks a direction with a chance in proportion to the brightness of the map
+// there. The distance is infinite. The light is divided by pi, the 1/pi of diffuse
+// reflection the camera's shading leaves to the lights, so that a surface under an
+// evenly lit map looks as bright as the map times its diffuse color.
+func (m *Map) Sample(point Vector.Vector, u, v float64) (Vector.Vector, float64, Vector.Vector) {
+	if m.total == 0 {
+		return Vector.Vector{}, math.Inf(1), Vector.Vector{}
+	}
+
+	y, rowOffset := sampleCDF(m.rowCDF, v)
+	x, columnOffset := sampleCDF(m.columnCDF[y], u)
+	s := (float64(x) + columnOffset) / float64(m.film.Width())
+	t := (float64(y) + rowOffset) / float64(m.film.Height())
+
+	direction := m.direction(s, t)
+	pdf := m.pdf(x, y, t)
+	if pdf == 0 {
+		return direction, math.Inf(1), Vector.Vector{}
+	}
+	return direction, math.Inf(1), m.Radiance(direction).Scale(1 / (math.Pi * pdf))
+}
+
+func (m *Map) String() string {
+	return fmt.Sprintf("{environment map: %dx%d, rotation: %g, intensity: %g}", m.film.Width(), m.film.Height(), m.rotation, m.intensity)
+}
+
+// weight is how likely a pixel is to be sampled, its luminance times the size of its row
+func (m *Map) weight(x, y int, sinTheta float64) float64 {
+	r, g, b := m.film.At(x, y)
+	return (0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)) * sinTheta
+}
+
+// pdf is the chance per unit solid angle of Sample picking a direction in pixel x, y
+// at height t of the image. The pixels are picked by weight, points evenly within
+// them, and the image maps onto the sphere with a stretch of 2 pi^2 sin(theta).
+func (m *Map) pdf(x, y int, t float64) float64 {
+	width, height := m.film.Width(), m.film.Height()
+	sinTheta := math.Sin(math.Pi * t)
+	if sinTheta == 0 {
+		return 0
+	}
+	rowSinTheta := math.Sin(math.Pi * (float64(y) + 0.5) / float64(height))
+	pixelChance := m.weight(x, y, rowSinTheta) / m.total
+	return pixelChance * float64(width*height) / (2 * math.Pi * math.Pi * sinTheta)
+}
+
+// direction turns a point on the image, s across and t down from 0 to 1, into a unit direction
+func (m *Map) direction(s, t float64) Vector.Vector {
+	theta := math.Pi * t
+	phi := 2*math.Pi*(s-0.5) + m.rotation
+	sinTheta := math.Sin(theta)
+	return *Vector.New(sinTheta*math.Sin(phi), -math.Cos(theta), sinTheta*math.Cos(phi))
+}
+
+// pixel finds the pixel a unit direction falls in
+func (m *Map) pixel(direction Vector.Vector) (int, int) {
+	width, height := m.film.Width(), m.film.Height()
+	theta := math.Acos(math.Max(-1, math.Min(1, -direction.Y())))
+	phi := math.Atan2(direction.X(), direction.Z()) - m.rotation
+
+	s := phi/(2*math.Pi) + 0.5
+	s -= math.Floor(s)
+	t := theta / math.Pi
+	return min(int(s*float64(width)), width-1), min(int(t*float64(height)), height-1)
+}
+
+// normalize scales a running sum to end at 1, one that sums to 0 is left alone
+func normalize(cdf []float64) {
+	total := cdf[len(cdf)-1]
+	if total == 0 {
+		return
+	}
+	for i := range cdf {
+		cdf[i] /= total
+	}
+}
+
+// sampleCDF finds the slot of a running sum u falls in and how far into it u is,
+// slots of weight 0 are never picked
+func sampleCDF(cdf []float64, u float64) (int, float64) {
+	// the first entry past u closes the slot u falls in
+	end, _ := slices.BinarySearchFunc(cdf, u, func(entry, target float64) int {
+		if entry <= target {
+			return -1
+		}
+		return 1
+	})
+	slot := min(max(end-1, 0), len(cdf)-2)
+	width := cdf[slot+1] - cdf[slot]
+	if width == 0 {
+		return slot, 0.5
+	}
+	return slot, math.Min(1, math.Max(0, (u-cdf[slot])/width))
+}
@@ -0,0 +1,238 @@
+package Environment
+
+import (
+	"goRay/Camera"
+	"goRay/Film"
+	"goRay/Object"
+	"goRay/Output"
+	"goRay/Sampler"
+	"goRay/Vector"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// numberedFilm holds x + 10y in the red channel of every pixel
+func numberedFilm(width, height int) *Film.Film {
+	film := Film.New(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			film.Set(x, y, float32(x+10*y), 1, 1)
+		}
+	}
+	return film
+}
+
+// sunFilm is a dim sky with a single bright pixel above the horizon
+func sunFilm() *Film.Film {
+	film := uniformFilm(64, 32, 0.05)
+	film.Set(40, 10, 5000, 4500, 4000)
+	return film
+}
+
+func uniformFilm(width, height int, value float32) *Film.Film {
+	film := Film.New(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			film.Set(x, y, value, value, value)
+		}
+	}
+	return film
+}
+
+func TestRadiance(t *testing.T) {
+	environment := New(numberedFilm(8, 4))
+
+	tests := []struct {
+		direction Vector.Vector
+		expected  float64
+	}{
+		{direction: *Vector.New(0, -1, 0), expected: 4},
+		{direction: *Vector.New(0, 1, 0), expected: 34},
+		{direction: *Vector.New(0, 0, 1), expected: 24},
+		{direction: *Vector.New(0, 0, 5), expected: 24},
+		{direction: *Vector.New(1, 0, 0), expected: 26},
+		{direction: *Vector.New(0, 0, -1), expected: 20},
+		{direction: *Vector.New(-1, -1, 0), expected: 12},
+	}
+
+	for i, tt := range tests {
+		if radiance := environment.Radiance(tt.direction); radiance.X() != tt.expected {
+			t.Errorf("Test %d: Expected %g, got %g", i+1, tt.expected, radiance.X())
+		}
+	}
+}
+
+func TestRotationAndIntensity(t *testing.T) {
+	plain := New(numberedFilm(8, 4))
+	turned := New(numberedFilm(8, 4))
+	turned.SetRotation(math.Pi / 2)
+	turned.SetIntensity(2)
+
+	// turning the map a quarter from +z towards +x brings what was at -x to +z
+	expected := plain.Radiance(*Vector.New(-1, 0, 0)).Scale(2)
+	if radiance := turned.Radiance(*Vector.New(0, 0, 1)); radiance != expected {
+		t.Errorf("Expected %v, got %v", expected, radiance)
+	}
+
+	// samples follow the rotation
+	for _, uv := range [][2]float64{{0.1, 0.3}, {0.6, 0.7}, {0.9, 0.2}} {
+		direction, _, _ := turned.Sample(Vector.Vector{}, uv[0], uv[1])
+		unturned, _, _ := plain.Sample(Vector.Vector{}, uv[0], uv[1])
+		// a quarter turn from +z towards +x
+		expected := *Vector.New(unturned.Z(), unturned.Y(), -unturned.X())
+		if difference := direction.Minus(expected); difference.Dot(difference) > 1e-20 {
+			t.Errorf("Expected the sample %v to turn to %v, got %v", unturned, expected, direction)
+		}
+		if radiance := turned.Radiance(direction); radiance != plain.Radiance(unturned).Scale(2) {
+			t.Errorf("Expected the turned sample to see the same pixel, got %v", radiance)
+		}
+	}
+}
+
+// irradiance averages the light falling on a surface facing normal over samples
+// spread evenly over the unit square
+func irradiance(environment *Map, normal Vector.Vector) float64 {
+	const gridSize = 128
+	sum := 0.0
+	for i := 0; i < gridSize; i++ {
+		for j := 0; j < gridSize; j++ {
+			u, v := (float64(i)+0.5)/gridSize, (float64(j)+0.5)/gridSize
+			direction, distance, radiance := environment.Sample(Vector.Vector{}, u, v)
+			if !math.IsInf(distance, 1) {
+				return math.NaN()
+			}
+			sum += radiance.X() * math.Max(0, normal.Dot(direction))
+		}
+	}
+	return sum / (gridSize * gridSize)
+}
+
+// integrate sums the light of every pixel falling on a surface facing normal, divided
+// by pi like Sample, by splitting each pixel into small patches
+func integrate(environment *Map, normal Vector.Vector) float64 {
+	const patches = 4
+	width, height := environment.film.Width(), environment.film.Height()
+	sum := 0.0
+	for y := 0; y < height*patches; y++ {
+		t := (float64(y) + 0.5) / float64(height*patches)
+		solidAngle := 2 * math.Pi * math.Pi * math.Sin(math.Pi*t) / float64(width*height*patches*patches)
+		for x := 0; x < width*patches; x++ {
+			direction := environment.direction((float64(x)+0.5)/float64(width*patches), t)
+			radiance := environment.Radiance(direction)
+			sum += radiance.X() * math.Max(0, normal.Dot(direction)) * solidAngle
+		}
+	}
+	return sum / math.Pi
+}
+
+func TestSampleIrradiance(t *testing.T) {
+	tests := []struct {
+		name   string
+		film   *Film.Film
+		normal Vector.Vector
+	}{
+		{name: "even sky facing up", film: uniformFilm(16, 8, 1), normal: *Vector.New(0, -1, 0)},
+		{name: "even sky facing sideways", film: uniformFilm(16, 8, 1), normal: *Vector.New(1, 0, 0)},
+		{name: "sun facing up", film: sunFilm(), normal: *Vector.New(0, -1, 0)},
+		{name: "sun at an angle", film: sunFilm(), normal: Vector.New(1, -1, 1).Normalize()},
+		{name: "numbered facing down", film: numberedFilm(8, 4), normal: *Vector.New(0, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			environment := New(tt.film)
+			expected := integrate(environment, tt.normal)
+			if got := irradiance(environment, tt.normal); math.Abs(got-expected) > 0.02*expected {
+				t.Errorf("Expected %g, got %g", expected, got)
+			}
+		})
+	}
+
+	// an evenly lit sky lights a surface as brightly as itself
+	if got := integrate(New(uniformFilm(16, 8, 1)), *Vector.New(0, -1, 0)); math.Abs(got-1) > 0.01 {
+		t.Errorf("Expected an even sky to give 1, got %g", got)
+	}
+}
+
+func TestSampleFavorsBrightPixels(t *testing.T) {
+	environment := New(sunFilm())
+	inSun := 0
+	const count = 1000
+	for i := 0; i < count; i++ {
+		direction, _, _ := environment.Sample(Vector.Vector{}, (float64(i)+0.5)/count, math.Mod(float64(i)*0.618034, 1))
+		if x, y := environment.pixel(direction); x == 40 && y == 10 {
+			inSun++
+		}
+	}
+	if inSun < count*9/10 {
+		t.Errorf("Expected most samples to land on the sun, got %d of %d", inSun, count)
+	}
+}
+
+func TestBlackMapGivesNoLight(t *testing.T) {
+	environment := New(uniformFilm(4, 2, 0))
+	if _, _, radiance := environment.Sample(Vector.Vector{}, 0.5, 0.5); radiance != (Vector.Vector{}) {
+		t.Errorf("Expected no light, got %v", radiance)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sky.hdr")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Output.EncodeHDR(file, numberedFilm(16, 8)); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	environment, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// straight ahead is pixel 8, 4, stored with an 8 bit mantissa
+	if radiance := environment.Radiance(*Vector.New(0, 0, 1)); math.Abs(radiance.X()-48) > 0.5 {
+		t.Errorf("Expected 48, got %v", radiance)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.hdr")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestEnvironmentLightsDiffuseSurfaces(t *testing.T) {
+	// a wall lit by an even sky of 1 reflects its diffuse color, whichever integrator
+	// follows the light
+	for _, integrator := range []Camera.Integrator{Camera.Whitted{}, Camera.PathTracer{}} {
+		camera := Camera.New(4, 4, Vector.Vector{})
+		camera.SetObject(Object.NewPlane(*Vector.New(0, 0, 30), *Vector.New(0, 0, -1), *Vector.New(0.5, 0.5, 0.5)))
+		camera.SetBackground(New(uniformFilm(16, 8, 1)))
+		camera.SetIntegrator(integrator)
+		camera.SetAntiAliasing(64)
+		camera.SetSampler(Sampler.NewSobol(1))
+
+		pixels := camera.CastRaysConcurrent()
+		sum := 0.0
+		for _, pixel := range pixels {
+			radiance := pixel.Radiance()
+			sum += radiance.X()
+		}
+		if average := sum / float64(len(pixels)); math.Abs(average-0.5) > 0.02 {
+			t.Errorf("%T: Expected the wall to be 0.5, got %g", integrator, average)
+		}
+	}
+}
+
+func TestEnvironmentIsSeenBehindObjects(t *testing.T) {
+	camera := Camera.New(1, 1, Vector.Vector{})
+	environment := New(numberedFilm(8, 4))
+	camera.SetBackground(environment)
+	if radiance := camera.CastRays()[0].Radiance(); radiance != environment.Radiance(*Vector.New(0, 0, 1)) {
+		t.Errorf("Expected the camera to see the map straight ahead, got %v", radiance)
+	}
+}
@@ -0,0 +1,87 @@
+package Film
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Film is a linear RGB framebuffer. 1 is the brightest a display shows, brighter
+// values are kept so tone mapping or HDR output can use them.
+type Film struct {
+	width  int
+	height int
+	// pixels holds red, green and blue for each pixel in row order
+	pixels []float32
+}
+
+func New(width, height int) *Film {
+	return &Film{
+		width:  width,
+		height: height,
+		pixels: make([]float32, width*height*3),
+	}
+}
+
+func (f *Film) Width() int {
+	return f.width
+}
+
+func (f *Film) Height() int {
+	return f.height
+}
+
+func (f *Film) Set(x, y int, r, g, b float32) {
+	i := (y*f.width + x) * 3
+	f.pixels[i], f.pixels[i+1], f.pixels[i+2] = r, g, b
+}
+
+func (f *Film) At(x, y int) (float32, float32, float32) {
+	i := (y*f.width + x) * 3
+	return f.pixels[i], f.pixels[i+1], f.pixels[i+2]
+}
+
+// Image tone maps and sRGB encodes the film into an 8 bit image
+func (f *Film) Image(toneMapper ToneMapper) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, f.width, f.height))
+	for y := 0; y < f.height; y++ {
+		for x := 0; x < f.width; x++ {
+			r, g, b := f.At(x, y)
+			img.SetRGBA(x, y, Develop(toneMapper, r, g, b))
+		}
+	}
+	return img
+}
+
+// Develop turns a linear color into what a display shows, tone mapped and sRGB encoded
+func Develop(toneMapper ToneMapper, r, g, b float32) color.RGBA {
+	r, g, b = toneMapper.Map(r, g, b)
+	return color.RGBA{R: toByte(SRGB(r)), G: toByte(SRGB(g)), B: toByte(SRGB(b)), A: 255}
+}
+
+// SRGB applies the sRGB transfer curve to a linear value between 0 and 1
+func SRGB(linear float32) float32 {
+	if linear <= 0.0031308 {
+		return 12.92 * linear
+	}
+	return float32(1.055*math.Pow(float64(linear), 1/2.4) - 0.055)
+}
+
+// Linear undoes SRGB, turning an sRGB encoded value between 0 and 1 back into linear light
+func Linear(encoded float32) float32 {
+	if encoded <= 0.04045 {
+		return encoded / 12.92
+	}
+	return float32(math.Pow((float64(encoded)+0.055)/1.055, 2.4))
+}
+
+// toByte rounds a value between 0 and 1 to 8 bits, anything outside is clamped
+func toByte(v float32) uint8 {
+	if !(v > 0) {
+		return 0
+	}
+	if v >= 1 {
+		return 255
+	}
+	return uint8(v*255 + 0.5)
+}
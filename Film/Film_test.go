@@ -0,0 +1,101 @@
+package Film
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestSRGB(t *testing.T) {
+	tests := []struct {
+		linear  float32
+		encoded float32
+	}{
+		{linear: 0, encoded: 0},
+		{linear: 0.002, encoded: 0.02584},
+		{linear: 0.0031308, encoded: 0.04045},
+		{linear: 0.18, encoded: 0.46135},
+		{linear: 0.5, encoded: 0.73536},
+		{linear: 1, encoded: 1},
+	}
+	for i, tt := range tests {
+		if got := SRGB(tt.linear); math.Abs(float64(got-tt.encoded)) > 1e-4 {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, tt.encoded, got)
+		}
+		if got := Linear(tt.encoded); math.Abs(float64(got-tt.linear)) > 1e-4 {
+			t.Errorf("Test %d: Expected %v back, got %v", i+1, tt.linear, got)
+		}
+	}
+}
+
+func TestToneMappers(t *testing.T) {
+	for _, name := range ToneMapperNames {
+		toneMapper, err := ToneMapperByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		previous := float32(-1)
+		for _, v := range []float32{-1, 0, 0.01, 0.18, 0.5, 1, 2, 8, 100, 1e6} {
+			r, g, b := toneMapper.Map(v, v, v)
+			if r < 0 || r > 1 || r != g || g != b {
+				t.Errorf("%s: Expected a grey between 0 and 1 for %v, got %v %v %v", name, v, r, g, b)
+			}
+			if r < previous {
+				t.Errorf("%s: Expected brighter input to stay brighter, %v mapped to %v after %v", name, v, r, previous)
+			}
+			previous = r
+		}
+	}
+
+	if _, err := ToneMapperByName("filmic"); err == nil {
+		t.Errorf("Expected an error for an unknown tone mapper")
+	}
+}
+
+func TestToneMapperValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		toneMapper ToneMapper
+		in         [3]float32
+		out        [3]float32
+	}{
+		{name: "clamp", toneMapper: Clamp{}, in: [3]float32{0.25, 3, -1}, out: [3]float32{0.25, 1, 0}},
+		{name: "reinhard", toneMapper: Reinhard{}, in: [3]float32{1, 1, 1}, out: [3]float32{0.5, 0.5, 0.5}},
+		{name: "reinhard keeps hue", toneMapper: Reinhard{}, in: [3]float32{0.4, 0.2, 0.1}, out: [3]float32{0.4 / 1.2353, 0.2 / 1.2353, 0.1 / 1.2353}},
+		{name: "aces", toneMapper: ACES{}, in: [3]float32{1, 0.18, 0}, out: [3]float32{0.8038, 0.2671, 0}},
+	}
+	for _, tt := range tests {
+		r, g, b := tt.toneMapper.Map(tt.in[0], tt.in[1], tt.in[2])
+		for channel, got := range []float32{r, g, b} {
+			if math.Abs(float64(got-tt.out[channel])) > 1e-3 {
+				t.Errorf("%s: Expected %v, got %v %v %v", tt.name, tt.out, r, g, b)
+				break
+			}
+		}
+	}
+}
+
+func TestImageIsToneMappedAndEncoded(t *testing.T) {
+	film := New(3, 2)
+	film.Set(0, 0, 4, 0.5, -2)
+	film.Set(2, 1, 0.18, 0.18, 0.18)
+
+	img := film.Image(Clamp{})
+	if img.Bounds().Dx() != 3 || img.Bounds().Dy() != 2 {
+		t.Fatalf("Expected a 3x2 image, got %v", img.Bounds())
+	}
+	tests := []struct {
+		x, y  int
+		color color.RGBA
+	}{
+		{x: 0, y: 0, color: color.RGBA{R: 255, G: 188, B: 0, A: 255}},
+		{x: 1, y: 0, color: color.RGBA{A: 255}},
+		{x: 2, y: 1, color: color.RGBA{R: 118, G: 118, B: 118, A: 255}},
+	}
+	for i, tt := range tests {
+		if got := img.RGBAAt(tt.x, tt.y); got != tt.color {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, tt.color, got)
+		}
+	}
+}
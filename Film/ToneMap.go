@@ -0,0 +1,69 @@
+package Film
+
+import "fmt"
+
+// ToneMapper compresses linear colors of any brightness into the 0 to 1 a display shows
+type ToneMapper interface {
+	Map(r, g, b float32) (float32, float32, float32)
+}
+
+// ToneMapperNames lists the tone mappers ToneMapperByName knows
+var ToneMapperNames = []string{"clamp", "reinhard", "aces"}
+
+func ToneMapperByName(name string) (ToneMapper, error) {
+	switch name {
+	case "clamp":
+		return Clamp{}, nil
+	case "reinhard":
+		return Reinhard{}, nil
+	case "aces":
+		return ACES{}, nil
+	}
+	return nil, fmt.Errorf("unknown tone mapper %q", name)
+}
+
+// Clamp clips every channel to 0 to 1, anything brighter burns out to white
+type Clamp struct{}
+
+func (Clamp) Map(r, g, b float32) (float32, float32, float32) {
+	return clamp(r), clamp(g), clamp(b)
+}
+
+// Reinhard maps luminance L to L/(1+L) and scales the color with it, which keeps
+// the hue of bright colors instead of washing them out channel by channel
+type Reinhard struct{}
+
+func (Reinhard) Map(r, g, b float32) (float32, float32, float32) {
+	luminance := 0.2126*r + 0.7152*g + 0.0722*b
+	if luminance <= 0 {
+		return 0, 0, 0
+	}
+	scale := 1 / (1 + luminance)
+	return clamp(r * scale), clamp(g * scale), clamp(b * scale)
+}
+
+// ACES is Krzysztof Narkowicz's fit of the ACES filmic curve, it has a gentle toe
+// and shoulder and slightly boosts contrast in the mid tones
+type ACES struct{}
+
+func (ACES) Map(r, g, b float32) (float32, float32, float32) {
+	return aces(r), aces(g), aces(b)
+}
+
+func aces(x float32) float32 {
+	const a, b, c, d, e = 2.51, 0.03, 2.43, 0.59, 0.14
+	if x <= 0 {
+		return 0
+	}
+	return clamp((x * (a*x + b)) / (x*(c*x+d) + e))
+}
+
+func clamp(v float32) float32 {
+	if !(v > 0) {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,177 @@
+package Light
+
+import (
+	"fmt"
+	"goRay/Vector"
+	"math"
+)
+
+// Sphere is a ball of light that casts soft shadows. It gives off as much light as a
+// point light of the same intensity and lights points outside it exactly like one at
+// its center, so it becomes a point light as the radius goes to 0. Like the other
+// lights it isn't seen by camera rays, an emissive sphere object is a lamp that is
+// seen and lights the scene by itself.
+type Sphere struct {
+	center    Vector.Vector
+	radius    float64
+	color     Vector.Vector
+	intensity float64
+}
+
+func NewSphere(center Vector.Vector, radius float64, colorVector Vector.Vector, intensity float64) *Sphere {
+	return &Sphere{
+		center:    center,
+		radius:    radius,
+		color:     colorVector,
+		intensity: intensity,
+	}
+}
+
+func (s *Sphere) Area() float64 {
+	return 4 * math.Pi * s.radius * s.radius
+}
+
+// Sample picks a direction evenly from the cone of directions in which point sees the
+// sphere, so every direction is weighted by the solid angle it covers. Points inside
+// the sphere get no light.
+func (s *Sphere) Sample(point Vector.Vector, u, v float64) (Vector.Vector, float64, Vector.Vector) {
+	toCenter := s.center.Minus(point)
+	distance := s.center.DistanceBetween(point)
+	if distance <= s.radius {
+		return Vector.Vector{}, 0, Vector.Vector{}
+	}
+	axis := toCenter.Scale(1 / distance)
+
+	// 1 - cos of the cone's half angle, written without the cancellation of the plain
+	// form so that tiny spheres keep their precision
+	sinSquaredMax := s.radius * s.radius / (distance * distance)
+	cosMax := math.Sqrt(1 - sinSquaredMax)
+	oneMinusCosMax := sinSquaredMax / (1 + cosMax)
+
+	cosTheta := 1 - u*oneMinusCosMax
+	sinTheta := math.Sqrt(math.Max(0, 1-cosTheta*cosTheta))
+	phi := 2 * math.Pi * v
+	tangent, bitangent := axis.OrthonormalBasis()
+	direction := axis.Scale(cosTheta).
+		Translate(tangent.Scale(sinTheta * math.Cos(phi))).
+		Translate(bitangent.Scale(sinTheta * math.Sin(phi))).
+		Normalize()
+
+	// the nearer of the two points where the direction crosses the sphere
+	nearest := distance*cosTheta - math.Sqrt(math.Max(0, s.radius*s.radius-distance*distance*sinTheta*sinTheta))
+
+	// a sphere seen from any side shows a disc of area pi r^2, so its radiance is the
+	// intensity over that. Multiplied by the solid angle 2 pi (1 - cosMax) of the cone
+	// the r^2 cancels, which keeps a sphere of radius 0 finite.
+	return direction, nearest, s.color.Scale(2 * s.intensity / (distance * distance * (1 + cosMax)))
+}
+
+func (s *Sphere) String() string {
+	return fmt.Sprintf("{sphere light: %s, radius: %g, intensity: %g}", s.center, s.radius, s.intensity)
+}
+
+// Rect is a flat parallelogram of light spanned by two edges from a corner, such as a
+// ceiling panel or a window. It shines from the side edgeU × edgeV points to and
+// gives off as much light straight out of that side as a point light of the same
+// intensity. Like the other lights it isn't seen by camera rays.
+type Rect struct {
+	corner       Vector.Vector
+	edgeU, edgeV Vector.Vector
+	normal       Vector.Vector
+	area         float64
+	color        Vector.Vector
+	intensity    float64
+}
+
+func NewRect(corner, edgeU, edgeV, colorVector Vector.Vector, intensity float64) *Rect {
+	cross := edgeU.Cross(edgeV)
+	return &Rect{
+		corner:    corner,
+		edgeU:     edgeU,
+		edgeV:     edgeV,
+		normal:    cross.Normalize(),
+		area:      cross.DistanceBetween(Vector.Vector{}),
+		color:     colorVector,
+		intensity: intensity,
+	}
+}
+
+func (r *Rect) Area() float64 {
+	return r.area
+}
+
+// Normal is the side the light shines from
+func (r *Rect) Normal() Vector.Vector {
+	return r.normal
+}
+
+// Sample picks a point evenly over the area and converts the area it stands for into
+// the solid angle it covers seen from point. Points behind the light get no light.
+func (r *Rect) Sample(point Vector.Vector, u, v float64) (Vector.Vector, float64, Vector.Vector) {
+	onLight := r.corner.Translate(r.edgeU.Scale(u)).Translate(r.edgeV.Scale(v))
+	toLight := onLight.Minus(point)
+	distance := onLight.DistanceBetween(point)
+	if distance == 0 {
+		return Vector.Vector{}, 0, Vector.Vector{}
+	}
+	direction := toLight.Scale(1 / distance)
+
+	cosLight := -r.normal.Dot(direction)
+	if cosLight <= 0 {
+		return direction, distance, Vector.Vector{}
+	}
+
+	// radiance intensity/area times the solid angle area cosLight/distance^2
+	return direction, distance, r.color.Scale(r.intensity * cosLight / (distance * distance))
+}
+
+func (r *Rect) String() string {
+	return fmt.Sprintf("{rect light: %s, edges: %s %s, intensity: %g}", r.corner, r.edgeU, r.edgeV, r.intensity)
+}
+
+// Shape is a surface that points can be picked on evenly by area, such as the
+// spheres, boxes, triangles and meshes of the scene
+type Shape interface {
+	Area() float64
+	// SampleSurface maps u and v from the unit square to a point on the surface and the
+	// outward unit normal there
+	SampleSurface(u, v float64) (Vector.Vector, Vector.Vector)
+}
+
+// Surface is the light given off by an emissive shape, every point of it glowing
+// with the same emission towards both of its sides, as emissive objects are seen by
+// camera rays. The shape is expected to be in the scene too, so that shadow rays
+// stopping just short of the light find the near side of a closed shape in front of
+// its far side.
+type Surface struct {
+	shape    Shape
+	emission Vector.Vector
+}
+
+func NewSurface(shape Shape, emission Vector.Vector) *Surface {
+	return &Surface{shape: shape, emission: emission}
+}
+
+func (s *Surface) Area() float64 {
+	return s.shape.Area()
+}
+
+// Sample picks a point evenly over the area and converts the area it stands for into
+// the solid angle it covers seen from point. Like an environment map the light is
+// divided by pi, the 1/pi of diffuse reflection the camera's shading leaves to the
+// lights, so that it matches the path tracer picking up the emission on a bounce.
+func (s *Surface) Sample(point Vector.Vector, u, v float64) (Vector.Vector, float64, Vector.Vector) {
+	onLight, normal := s.shape.SampleSurface(u, v)
+	distance := onLight.DistanceBetween(point)
+	if distance == 0 {
+		return Vector.Vector{}, 0, Vector.Vector{}
+	}
+	direction := onLight.Minus(point).Scale(1 / distance)
+
+	cosLight := math.Abs(normal.Dot(direction))
+	return direction, distance, s.emission.Scale(s.shape.Area() * cosLight / (math.Pi * distance * distance))
+}
+
+func (s *Surface) String() string {
+	return fmt.Sprintf("{surface light: %v, emission: %s}", s.shape, s.emission)
+}
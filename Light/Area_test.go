@@ -0,0 +1,191 @@
+package Light
+
+import (
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+// gridSize squared samples spread evenly over the unit square average out the light
+// of an area light
+const gridSize = 64
+
+// irradiance averages the light falling on a surface at point facing normal, over
+// samples spread evenly over the light
+func irradiance(light Light, point, normal Vector.Vector) float64 {
+	sum := 0.0
+	for i := 0; i < gridSize; i++ {
+		for j := 0; j < gridSize; j++ {
+			u, v := (float64(i)+0.5)/gridSize, (float64(j)+0.5)/gridSize
+			direction, _, radiance := light.Sample(point, u, v)
+			sum += radiance.X() * math.Max(0, normal.Dot(direction))
+		}
+	}
+	return sum / (gridSize * gridSize)
+}
+
+func TestSphereReducesToPoint(t *testing.T) {
+	center := *Vector.New(3, -10, 4)
+	point := NewPoint(center, white, 100)
+	shaded := []Vector.Vector{*Vector.New(0, 0, 0), *Vector.New(-20, 5, 30), *Vector.New(3, -7, 4)}
+
+	for i, radius := range []float64{0.1, 1e-3, 1e-6, 0} {
+		sphere := NewSphere(center, radius, white, 100)
+		for _, p := range shaded {
+			expectedDirection, expectedDistance, expectedRadiance := point.Sample(p, 0, 0)
+			for _, uv := range [][2]float64{{0, 0}, {0.5, 0.25}, {0.99, 0.99}} {
+				direction, distance, radiance := sphere.Sample(p, uv[0], uv[1])
+
+				if difference := direction.Minus(expectedDirection).Dot(direction.Minus(expectedDirection)); math.Sqrt(difference) > 2*radius/3+1e-12 {
+					t.Errorf("Test %d: Expected direction %v, got %v", i, expectedDirection, direction)
+				}
+				if math.Abs(distance-expectedDistance) > radius+1e-12 {
+					t.Errorf("Test %d: Expected distance %g, got %g", i, expectedDistance, distance)
+				}
+				if math.Abs(radiance.X()-expectedRadiance.X()) > radius*expectedRadiance.X()+1e-12 {
+					t.Errorf("Test %d: Expected radiance %v, got %v", i, expectedRadiance, radiance)
+				}
+			}
+		}
+	}
+}
+
+func TestSphereIrradianceMatchesPoint(t *testing.T) {
+	// a sphere wholly above the surface lights it exactly like a point at its center
+	center := *Vector.New(0, -10, 0)
+	point := NewPoint(center, white, 100)
+	normal := *Vector.New(0, -1, 0)
+
+	tests := []struct {
+		radius float64
+		normal Vector.Vector
+	}{
+		{radius: 5, normal: normal},
+		{radius: 9, normal: normal},
+		{radius: 4, normal: Vector.New(1, -2, 0).Normalize()},
+	}
+
+	for i, tt := range tests {
+		expected := irradiance(point, Vector.Vector{}, tt.normal)
+		got := irradiance(NewSphere(center, tt.radius, white, 100), Vector.Vector{}, tt.normal)
+		if math.Abs(got-expected) > 0.01*expected {
+			t.Errorf("Test %d: Expected irradiance %g, got %g", i, expected, got)
+		}
+	}
+}
+
+func TestSphereSamplesHitTheSphere(t *testing.T) {
+	center := *Vector.New(0, -10, 0)
+	sphere := NewSphere(center, 4, white, 100)
+
+	for i := 0; i < gridSize; i++ {
+		u, v := (float64(i)+0.5)/gridSize, float64(i*7%gridSize)/gridSize
+		direction, distance, _ := sphere.Sample(Vector.Vector{}, u, v)
+		onLight := direction.Scale(distance)
+		if r := onLight.DistanceBetween(center); math.Abs(r-4) > 1e-9 {
+			t.Errorf("Sample %d: Expected a point on the sphere, got one %g from the center", i, r)
+		}
+		if onLight.Minus(center).Dot(direction) > 0 {
+			t.Errorf("Sample %d: Expected the near side of the sphere", i)
+		}
+	}
+}
+
+func TestSphereInside(t *testing.T) {
+	sphere := NewSphere(*Vector.New(0, -10, 0), 4, white, 100)
+	if _, _, radiance := sphere.Sample(*Vector.New(1, -9, 0), 0.5, 0.5); radiance != (Vector.Vector{}) {
+		t.Errorf("Expected no light inside the sphere, got %v", radiance)
+	}
+}
+
+// polygonIrradiance is Lambert's closed form for the irradiance from a polygon of
+// constant radiance on a surface at point facing normal
+func polygonIrradiance(vertices []Vector.Vector, radiance float64, point, normal Vector.Vector) float64 {
+	sum := 0.0
+	for i := range vertices {
+		a := vertices[i].Minus(point).Normalize()
+		b := vertices[(i+1)%len(vertices)].Minus(point).Normalize()
+		angle := math.Acos(math.Max(-1, math.Min(1, a.Dot(b))))
+		sum += angle * normal.Dot(a.Cross(b).Normalize())
+	}
+	return radiance * math.Abs(sum) / 2
+}
+
+func TestRectIrradiance(t *testing.T) {
+	corner, edgeU, edgeV := *Vector.New(-2, -10, -3), *Vector.New(0, 0, 6), *Vector.New(4, 0, 0)
+	light := NewRect(corner, edgeU, edgeV, white, 240)
+	vertices := []Vector.Vector{corner, corner.Translate(edgeU), corner.Translate(edgeU).Translate(edgeV), corner.Translate(edgeV)}
+	radiance := 240 / light.Area()
+
+	tests := []struct {
+		point  Vector.Vector
+		normal Vector.Vector
+	}{
+		{point: *Vector.New(0, 0, 0), normal: *Vector.New(0, -1, 0)},
+		{point: *Vector.New(0, -6, 0), normal: *Vector.New(0, -1, 0)},
+		{point: *Vector.New(5, 0, 2), normal: Vector.New(-1, -1, 0).Normalize()},
+	}
+
+	for i, tt := range tests {
+		expected := polygonIrradiance(vertices, radiance, tt.point, tt.normal)
+		if got := irradiance(light, tt.point, tt.normal); math.Abs(got-expected) > 0.01*expected {
+			t.Errorf("Test %d: Expected irradiance %g, got %g", i, expected, got)
+		}
+	}
+}
+
+func TestRectShinesFromOneSide(t *testing.T) {
+	light := NewRect(*Vector.New(-1, 0, -1), *Vector.New(0, 0, 2), *Vector.New(2, 0, 0), white, 10)
+	if normal := light.Normal(); normal != *Vector.New(0, 1, 0) {
+		t.Errorf("Expected the light to face +y, got %v", normal)
+	}
+
+	if _, _, radiance := light.Sample(*Vector.New(0, 5, 0), 0.5, 0.5); radiance != *Vector.New(0.4, 0.4, 0.4) {
+		t.Errorf("Expected the lit side straight out to get intensity/distance^2, got %v", radiance)
+	}
+	if _, _, radiance := light.Sample(*Vector.New(0, -5, 0), 0.5, 0.5); radiance != (Vector.Vector{}) {
+		t.Errorf("Expected no light behind the rect, got %v", radiance)
+	}
+}
+
+// parallelogram is a shape spanned by two edges from a corner
+type parallelogram struct {
+	corner, edgeU, edgeV Vector.Vector
+}
+
+func (p parallelogram) Area() float64 {
+	cross := p.edgeU.Cross(p.edgeV)
+	return math.Sqrt(cross.Dot(cross))
+}
+
+func (p parallelogram) SampleSurface(u, v float64) (Vector.Vector, Vector.Vector) {
+	return p.corner.Translate(p.edgeU.Scale(u)).Translate(p.edgeV.Scale(v)), p.edgeU.Cross(p.edgeV).Normalize()
+}
+
+func TestSurfaceIrradiance(t *testing.T) {
+	shape := parallelogram{corner: *Vector.New(-2, -10, -3), edgeU: *Vector.New(0, 0, 6), edgeV: *Vector.New(4, 0, 0)}
+	light := NewSurface(shape, *Vector.New(2, 2, 2))
+	vertices := []Vector.Vector{shape.corner, shape.corner.Translate(shape.edgeU), shape.corner.Translate(shape.edgeU).Translate(shape.edgeV), shape.corner.Translate(shape.edgeV)}
+	// the 1/pi of diffuse reflection is left to the lights
+	radiance := 2 / math.Pi
+
+	tests := []struct {
+		point  Vector.Vector
+		normal Vector.Vector
+	}{
+		{point: *Vector.New(0, 0, 0), normal: *Vector.New(0, -1, 0)},
+		{point: *Vector.New(5, 0, 2), normal: Vector.New(-1, -1, 0).Normalize()},
+		// the surface glows from both sides
+		{point: *Vector.New(0, -16, 0), normal: *Vector.New(0, 1, 0)},
+	}
+
+	for i, tt := range tests {
+		expected := polygonIrradiance(vertices, radiance, tt.point, tt.normal)
+		if got := irradiance(light, tt.point, tt.normal); math.Abs(got-expected) > 0.01*expected {
+			t.Errorf("Test %d: Expected irradiance %g, got %g", i, expected, got)
+		}
+	}
+	if area := light.Area(); area != 24 {
+		t.Errorf("Expected the area of the shape, got %g", area)
+	}
+}
@@ -0,0 +1,78 @@
+package Light
+
+import (
+	"fmt"
+	"goRay/Vector"
+	"math"
+)
+
+// Light is a source of direct illumination
+type Light interface {
+	// Sample picks a point on the light with u and v from the unit square and returns
+	// the unit direction from point towards it, the distance the light travels to get
+	// there and the light arriving at point, already divided by the chance of picking
+	// that direction. Averaging samples spread over the unit square gives the light
+	// arriving from all of the light. Occlusion is left to the caller.
+	Sample(point Vector.Vector, u, v float64) (Vector.Vector, float64, Vector.Vector)
+}
+
+// AreaLight is a light with a size. Its samples spread over its surface, so it takes
+// several of them to get soft shadows right.
+type AreaLight interface {
+	Light
+	// Area is the surface area of the light, a light of area 0 behaves like a point
+	Area() float64
+}
+
+// Point shines equally in every direction from a position, falling off with the
+// square of the distance. An intensity of 1 fully lights a surface 1 unit away.
+type Point struct {
+	position  Vector.Vector
+	color     Vector.Vector
+	intensity float64
+}
+
+func NewPoint(position, colorVector Vector.Vector, intensity float64) *Point {
+	return &Point{
+		position:  position,
+		color:     colorVector,
+		intensity: intensity,
+	}
+}
+
+// Sample ignores u and v, all the light comes from the one position
+func (p *Point) Sample(point Vector.Vector, u, v float64) (Vector.Vector, float64, Vector.Vector) {
+	distance := p.position.DistanceBetween(point)
+	direction := p.position.Minus(point).Normalize()
+	return direction, distance, p.color.Scale(p.intensity / (distance * distance))
+}
+
+func (p *Point) String() string {
+	return fmt.Sprintf("{point light: %s, intensity: %g}", p.position, p.intensity)
+}
+
+// Directional is a light infinitely far away, such as the sun, whose rays all
+// travel in the same direction and don't fall off
+type Directional struct {
+	direction Vector.Vector
+	color     Vector.Vector
+	intensity float64
+}
+
+// NewDirectional takes the direction the light travels in
+func NewDirectional(direction, colorVector Vector.Vector, intensity float64) *Directional {
+	return &Directional{
+		direction: direction.Normalize(),
+		color:     colorVector,
+		intensity: intensity,
+	}
+}
+
+// Sample ignores u and v, all the light comes from the one direction
+func (d *Directional) Sample(point Vector.Vector, u, v float64) (Vector.Vector, float64, Vector.Vector) {
+	return d.direction.Reverse(), math.Inf(1), d.color.Scale(d.intensity)
+}
+
+func (d *Directional) String() string {
+	return fmt.Sprintf("{directional light: %s, intensity: %g}", d.direction, d.intensity)
+}
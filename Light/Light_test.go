@@ -0,0 +1,69 @@
+package Light
+
+import (
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+var white = *Vector.New(1, 1, 1)
+
+func TestPointSample(t *testing.T) {
+	light := NewPoint(*Vector.New(0, -10, 0), white, 100)
+
+	tests := []struct {
+		point     Vector.Vector
+		direction Vector.Vector
+		distance  float64
+		radiance  float64
+	}{
+		{
+			point:     *Vector.New(0, 0, 0),
+			direction: *Vector.New(0, -1, 0),
+			distance:  10,
+			radiance:  1,
+		},
+		{
+			point:     *Vector.New(0, 10, 0),
+			direction: *Vector.New(0, -1, 0),
+			distance:  20,
+			radiance:  0.25,
+		},
+		{
+			point:     *Vector.New(10, -10, 0),
+			direction: *Vector.New(-1, 0, 0),
+			distance:  10,
+			radiance:  1,
+		},
+	}
+
+	for i, tt := range tests {
+		direction, distance, radiance := light.Sample(tt.point, 0.3, 0.7)
+		if direction != tt.direction {
+			t.Errorf("Test %d: Expected direction %v, got %v", i, tt.direction, direction)
+		}
+		if distance != tt.distance {
+			t.Errorf("Test %d: Expected distance %g, got %g", i, tt.distance, distance)
+		}
+		if radiance != *Vector.New(tt.radiance, tt.radiance, tt.radiance) {
+			t.Errorf("Test %d: Expected radiance %g, got %v", i, tt.radiance, radiance)
+		}
+	}
+}
+
+func TestDirectionalSample(t *testing.T) {
+	light := NewDirectional(*Vector.New(0, 2, 0), *Vector.New(1, 0.5, 0), 2)
+
+	for i, point := range []Vector.Vector{*Vector.New(0, 0, 0), *Vector.New(1000, -50, 3)} {
+		direction, distance, radiance := light.Sample(point, 0.9, 0.1)
+		if direction != *Vector.New(0, -1, 0) {
+			t.Errorf("Test %d: Expected to point against the light's travel, got %v", i, direction)
+		}
+		if !math.IsInf(distance, 1) {
+			t.Errorf("Test %d: Expected an infinite distance, got %g", i, distance)
+		}
+		if radiance != *Vector.New(2, 1, 0) {
+			t.Errorf("Test %d: Expected radiance not to fall off, got %v", i, radiance)
+		}
+	}
+}
@@ -1,36 +1,312 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"goRay/Camera"
-	"goRay/Object"
-	"goRay/Renderer"
-	"goRay/Vector"
+	"goRay/DebugView"
+	"goRay/Film"
+	"goRay/Output"
+	"goRay/Scene"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
 )
 
+const usage = `usage:
+  goRay [view] [--scene file]  open the interactive SDL viewer, missing when built with -tags headless
+  goRay render [flags]         render a single frame to an image file without a window
+
+render flags:
+`
+
 func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		return viewCommand(args, stderr)
+	}
+
+	switch args[0] {
+	case "view":
+		return viewCommand(args[1:], stderr)
+	case "render":
+		return renderCommand(args[1:], stderr)
+	case "-h", "--help", "help":
+		flags, _ := newRenderFlags(stderr)
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+	fmt.Fprint(stderr, usage)
+	return 2
+}
+
+func viewCommand(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("view", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	scenePath := flags.String("scene", "", "scene file to open, the built in scene when empty")
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	camera, err := loadScene(*scenePath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if err := openViewer(camera); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+type renderOptions struct {
+	scene        string
+	out          string
+	width        int
+	height       int
+	antiAliasing int
+	fieldOfView  float64
+	projection   string
+	sampler      string
+	seed         int64
+	toneMapper   string
+	integrator   string
+	quiet        bool
+	miniMap      string
+}
+
+func newRenderFlags(stderr io.Writer) (*flag.FlagSet, *renderOptions) {
+	options := &renderOptions{}
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&options.scene, "scene", "", "scene file to render, the built in scene when empty")
+	flags.StringVar(&options.out, "out", "frame.png", "output file, format chosen by extension (.png, .jpg, .ppm, or .hdr and .exr for linear HDR)")
+	flags.IntVar(&options.width, "width", 0, "image width in pixels, overrides the scene")
+	flags.IntVar(&options.height, "height", 0, "image height in pixels, overrides the scene")
+	flags.IntVar(&options.antiAliasing, "aa", -1, "anti-aliasing samples per pixel, 0 disables, overrides the scene")
+	flags.Float64Var(&options.fieldOfView, "fov", 0, "horizontal field of view in degrees, or the image circle of a fisheye, overrides the scene")
+	flags.StringVar(&options.projection, "projection", "", "camera projection, one of "+strings.Join(Scene.ProjectionTypes, ", ")+", overrides the scene")
+	flags.StringVar(&options.sampler, "sampler", "", "anti-aliasing sampler, one of "+strings.Join(Scene.SamplerTypes, ", ")+", overrides the scene")
+	flags.Int64Var(&options.seed, "seed", -1, "sampler seed, the same seed renders the same image, overrides the scene")
+	flags.StringVar(&options.integrator, "integrator", "", "how light is computed, one of "+strings.Join(Camera.IntegratorNames, ", ")+", overrides the scene")
+	flags.StringVar(&options.toneMapper, "tonemap", "clamp", "tone mapper for 8 bit output, one of "+strings.Join(Film.ToneMapperNames, ", "))
+	flags.StringVar(&options.miniMap, "minimap", "", "also write the top down debug view of the primary rays and objects to this image file")
+	flags.BoolVar(&options.quiet, "quiet", false, "don't print a progress bar")
+	return flags, options
+}
 
-	h := 100
-	w := 100
+func renderCommand(args []string, stderr io.Writer) int {
+	flags, options := newRenderFlags(stderr)
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
 
-	camOrigin := Vector.New(0, 0, 100)
-	camera := Camera.New(w, h, *camOrigin)
-	camera.SetAntiAliasing(15)
+	if options.width < 0 || options.height < 0 {
+		fmt.Fprintf(stderr, "invalid size %dx%d\n", options.width, options.height)
+		return 2
+	}
+	if options.projection != "" && !slices.Contains(Scene.ProjectionTypes, options.projection) {
+		fmt.Fprintf(stderr, "unknown projection %q, expected one of %s\n", options.projection, strings.Join(Scene.ProjectionTypes, ", "))
+		return 2
+	}
+	if options.integrator != "" && !slices.Contains(Camera.IntegratorNames, options.integrator) {
+		fmt.Fprintf(stderr, "unknown integrator %q, expected one of %s\n", options.integrator, strings.Join(Camera.IntegratorNames, ", "))
+		return 2
+	}
+	if options.sampler != "" && !slices.Contains(Scene.SamplerTypes, options.sampler) {
+		fmt.Fprintf(stderr, "unknown sampler %q, expected one of %s\n", options.sampler, strings.Join(Scene.SamplerTypes, ", "))
+		return 2
+	}
+	if _, err := Output.FormatFromPath(options.out); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if options.miniMap != "" {
+		if format, err := Output.FormatFromPath(options.miniMap); err != nil || format.HighDynamicRange() {
+			fmt.Fprintf(stderr, "unsupported minimap file %s, expected .png, .jpg or .ppm\n", options.miniMap)
+			return 2
+		}
+	}
+	toneMapper, err := Film.ToneMapperByName(options.toneMapper)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
 
-	white := *Vector.New(1.0, 1.0, 1.0)
-	red := *Vector.New(1.0, 0.0, 0.0)
-	green := *Vector.New(0.0, 1.0, 0.0)
-	purple := *Vector.New(1.0, 0.0, 1.0)
+	description, err := loadDescription(options.scene)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if options.width > 0 {
+		description.Camera.Width = options.width
+	}
+	if options.height > 0 {
+		description.Camera.Height = options.height
+	}
+	if options.antiAliasing >= 0 {
+		description.Camera.AntiAliasing = options.antiAliasing
+	}
+	if options.integrator != "" {
+		description.Camera.Integrator = options.integrator
+	}
+	if options.projection != "" {
+		setProjection(&description.Camera, options.projection)
+	}
+	if options.sampler != "" || options.seed >= 0 {
+		sampler := Scene.SamplerDescription{Type: "random"}
+		if description.Camera.Sampler != nil {
+			sampler = *description.Camera.Sampler
+		}
+		if options.sampler != "" {
+			sampler.Type = options.sampler
+		}
+		if options.seed >= 0 {
+			sampler.Seed = options.seed
+		}
+		description.Camera.Sampler = &sampler
+	}
+	if options.fieldOfView != 0 {
+		if err := setFieldOfView(&description.Camera, options.fieldOfView); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	// the overrides follow the same rules as the scene file
+	if err := description.Validate(); err != nil {
+		fmt.Fprintf(stderr, "invalid flags: %v\n", err)
+		return 2
+	}
 
-	planet := Object.NewSphere(*Vector.New(0, 1005, 0), white, 1000)
-	sphere := Object.NewSphere(*Vector.New(0, 0, 50), red, 10)
-	sphere1 := Object.NewSphere(*Vector.New(20, 10, 50), green, 10)
-	sphere2 := Object.NewSphere(*Vector.New(40, 5, 50), purple, 10)
+	camera, err := description.Build()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
 
-	camera.SetObject(planet)
-	camera.SetObject(sphere)
-	camera.SetObject(sphere1)
-	camera.SetObject(sphere2)
+	if !options.quiet {
+		camera.SetProgressObserver(newProgressBar(stderr))
+	}
 
-	Renderer.Render(int32(w), int32(h), *camera)
+	// an interrupt stops the render instead of killing the process halfway through writing
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	film, err := camera.RenderContext(ctx)
+	if !options.quiet {
+		fmt.Fprintln(stderr)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "render interrupted")
+		return 130
+	}
+
+	if err := Output.WriteFilm(options.out, film, toneMapper); err != nil {
+		fmt.Fprintf(stderr, "writing %s: %v\n", options.out, err)
+		return 1
+	}
+	if options.miniMap != "" {
+		if err := Output.WriteFile(options.miniMap, DebugView.MiniMap(camera, miniMapSize, miniMapSize)); err != nil {
+			fmt.Fprintf(stderr, "writing %s: %v\n", options.miniMap, err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// setProjection switches the camera to another type of projection, keeping the
+// scene's projection settings when the type stays the same and dropping the ones the
+// new type doesn't use
+func setProjection(camera *Scene.CameraDescription, projectionType string) {
+	projection := Scene.ProjectionDescription{Type: "perspective"}
+	if camera.Projection != nil {
+		projection = *camera.Projection
+	}
+	if projection.Type == projectionType {
+		return
+	}
+
+	projection.Type = projectionType
+	if projectionType != "orthographic" {
+		projection.Height = 0
+	}
+	if projectionType != "fisheye" {
+		projection.FieldOfView = 0
+	}
+	if projectionType != "perspective" {
+		camera.FieldOfView, camera.VerticalFieldOfView = 0, 0
+	}
+	camera.Projection = &projection
+}
+
+// setFieldOfView puts degrees where the camera's projection reads its field of view
+func setFieldOfView(camera *Scene.CameraDescription, degrees float64) error {
+	projectionType := "perspective"
+	if camera.Projection != nil {
+		projectionType = camera.Projection.Type
+	}
+
+	switch projectionType {
+	case "perspective":
+		camera.FieldOfView, camera.VerticalFieldOfView = degrees, 0
+	case "fisheye":
+		camera.Projection.FieldOfView = degrees
+	default:
+		return fmt.Errorf("--fov is not used by the %s projection", projectionType)
+	}
+	return nil
+}
+
+// miniMapSize is the side of the minimap image, the same as the viewer window
+const miniMapSize = 600
+
+// progressBarWidth is how many characters the bar itself takes
+const progressBarWidth = 30
+
+// newProgressBar redraws a progress bar on a single line of w at most every 100ms and
+// for the last tile, the line is left without a newline
+func newProgressBar(w io.Writer) Camera.ProgressObserver {
+	var lastDraw time.Time
+	return func(progress Camera.Progress) {
+		if progress.TilesDone < progress.Tiles && time.Since(lastDraw) < 100*time.Millisecond {
+			return
+		}
+		lastDraw = time.Now()
+
+		filled := int(progress.Fraction() * progressBarWidth)
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		fmt.Fprintf(w, "\r[%s] %v", bar, progress)
+	}
+}
+
+func loadDescription(path string) (*Scene.Description, error) {
+	if path == "" {
+		return Scene.DefaultDescription(), nil
+	}
+	return Scene.LoadDescription(path)
+}
 
+func loadScene(path string) (*Camera.Camera, error) {
+	description, err := loadDescription(path)
+	if err != nil {
+		return nil, err
+	}
+	return description.Build()
 }
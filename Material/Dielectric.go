@@ -0,0 +1,81 @@
+package Material
+
+import (
+	"fmt"
+	"goRay/Vector"
+	"math"
+)
+
+// Refractive is implemented by transparent materials. The camera splits rays
+// hitting them into a reflected and a refracted part instead of shading the surface.
+type Refractive interface {
+	IndexOfRefraction() float64
+	// Tint filters the light passing through the surface
+	Tint() Vector.Vector
+}
+
+// Dielectric is a clear material such as glass (1.5) or water (1.33)
+type Dielectric struct {
+	indexOfRefraction float64
+	tint              Vector.Vector
+}
+
+func NewDielectric(indexOfRefraction float64, tint Vector.Vector) *Dielectric {
+	return &Dielectric{
+		indexOfRefraction: indexOfRefraction,
+		tint:              tint,
+	}
+}
+
+func (d *Dielectric) IndexOfRefraction() float64 {
+	return d.indexOfRefraction
+}
+
+func (d *Dielectric) Tint() Vector.Vector {
+	return d.tint
+}
+
+// Diffuse is black, all of the light is either reflected or refracted
+func (d *Dielectric) Diffuse() Vector.Vector {
+	return Vector.Vector{}
+}
+
+func (d *Dielectric) Specular() Vector.Vector {
+	return Vector.Vector{}
+}
+
+func (d *Dielectric) Shininess() float64 {
+	return 0
+}
+
+func (d *Dielectric) Ambient() Vector.Vector {
+	return Vector.Vector{}
+}
+
+// Reflectivity is 0 as the reflected share comes from Fresnel instead
+func (d *Dielectric) Reflectivity() float64 {
+	return 0
+}
+
+func (d *Dielectric) String() string {
+	return fmt.Sprintf("{dielectric, ior: %g, tint: %s}", d.indexOfRefraction, d.tint)
+}
+
+// Fresnel returns the share of unpolarised light reflected off the boundary
+// between refractive indices etaIncident and etaTransmitted. cosIncident is the
+// cosine between the incoming ray and the surface normal. Under total internal
+// reflection everything is reflected.
+func Fresnel(cosIncident, etaIncident, etaTransmitted float64) float64 {
+	cosIncident = math.Min(1, math.Abs(cosIncident))
+	sinTransmitted := etaIncident / etaTransmitted * math.Sqrt(math.Max(0, 1-cosIncident*cosIncident))
+	if sinTransmitted >= 1 {
+		return 1
+	}
+	cosTransmitted := math.Sqrt(1 - sinTransmitted*sinTransmitted)
+
+	parallel := (etaTransmitted*cosIncident - etaIncident*cosTransmitted) /
+		(etaTransmitted*cosIncident + etaIncident*cosTransmitted)
+	perpendicular := (etaIncident*cosIncident - etaTransmitted*cosTransmitted) /
+		(etaIncident*cosIncident + etaTransmitted*cosTransmitted)
+	return (parallel*parallel + perpendicular*perpendicular) / 2
+}
@@ -0,0 +1,51 @@
+package Material
+
+import (
+	"fmt"
+	"goRay/Vector"
+)
+
+// Emitter is implemented by materials that give off light of their own
+type Emitter interface {
+	// Emission is the light leaving every point of the surface, in the same units as colors
+	Emission() Vector.Vector
+}
+
+// Emissive is a glowing surface that gives off light without reflecting any, such
+// as a lamp shade. Emissive spheres, boxes and meshes light the scene like area
+// lights, emissive planes only through the bounces of the path tracer.
+type Emissive struct {
+	emission Vector.Vector
+}
+
+func NewEmissive(emission Vector.Vector) *Emissive {
+	return &Emissive{emission: emission}
+}
+
+func (e *Emissive) Emission() Vector.Vector {
+	return e.emission
+}
+
+func (e *Emissive) Diffuse() Vector.Vector {
+	return Vector.Vector{}
+}
+
+func (e *Emissive) Specular() Vector.Vector {
+	return Vector.Vector{}
+}
+
+func (e *Emissive) Shininess() float64 {
+	return 0
+}
+
+func (e *Emissive) Ambient() Vector.Vector {
+	return Vector.Vector{}
+}
+
+func (e *Emissive) Reflectivity() float64 {
+	return 0
+}
+
+func (e *Emissive) String() string {
+	return fmt.Sprintf("{emissive: %s}", e.emission)
+}
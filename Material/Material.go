@@ -0,0 +1,106 @@
+package Material
+
+import (
+	"fmt"
+	"goRay/Texture"
+	"goRay/Vector"
+)
+
+// Material describes how a surface responds to light, using the terms of the
+// Blinn-Phong reflection model
+type Material interface {
+	Diffuse() Vector.Vector
+	Specular() Vector.Vector
+	// Shininess is the Blinn-Phong exponent, larger values give smaller, sharper highlights
+	Shininess() float64
+	// Ambient is added regardless of the lights, it stops unlit sides going completely black
+	Ambient() Vector.Vector
+	// Reflectivity is the share of the color taken from the mirror reflection, from 0 to 1
+	Reflectivity() float64
+}
+
+// Textured is implemented by materials whose diffuse color can vary over the surface,
+// Texture is nil when it doesn't
+type Textured interface {
+	Texture() Texture.Texture
+}
+
+// NormalMapped is implemented by materials that bend the shading normal with a normal
+// or bump map, NormalMap is nil when they don't
+type NormalMapped interface {
+	NormalMap() Texture.Perturbation
+}
+
+type Phong struct {
+	diffuse      Vector.Vector
+	specular     Vector.Vector
+	shininess    float64
+	ambient      Vector.Vector
+	reflectivity float64
+	texture      Texture.Texture
+	normalMap    Texture.Perturbation
+}
+
+// NewDiffuse creates a matte material with no highlights or ambient term
+func NewDiffuse(colorVector Vector.Vector) *Phong {
+	return &Phong{diffuse: colorVector}
+}
+
+func NewPhong(diffuse, specular Vector.Vector, shininess float64, ambient Vector.Vector) *Phong {
+	return &Phong{
+		diffuse:   diffuse,
+		specular:  specular,
+		shininess: shininess,
+		ambient:   ambient,
+	}
+}
+
+func (p *Phong) Diffuse() Vector.Vector {
+	return p.diffuse
+}
+
+func (p *Phong) Specular() Vector.Vector {
+	return p.specular
+}
+
+func (p *Phong) Shininess() float64 {
+	return p.shininess
+}
+
+func (p *Phong) Ambient() Vector.Vector {
+	return p.ambient
+}
+
+func (p *Phong) Reflectivity() float64 {
+	return p.reflectivity
+}
+
+// SetReflectivity turns the material into a mirror, 1 reflects everything and
+// hides the diffuse and specular terms
+func (p *Phong) SetReflectivity(reflectivity float64) {
+	p.reflectivity = reflectivity
+}
+
+// SetTexture replaces the diffuse color with the texture's color at each hit point,
+// Diffuse stays the color of the material as a whole
+func (p *Phong) SetTexture(texture Texture.Texture) {
+	p.texture = texture
+}
+
+func (p *Phong) Texture() Texture.Texture {
+	return p.texture
+}
+
+// SetNormalMap bends the normal the surface is shaded with at each hit point, the
+// geometry stays as it is
+func (p *Phong) SetNormalMap(normalMap Texture.Perturbation) {
+	p.normalMap = normalMap
+}
+
+func (p *Phong) NormalMap() Texture.Perturbation {
+	return p.normalMap
+}
+
+func (p *Phong) String() string {
+	return fmt.Sprintf("{diffuse: %s, specular: %s, shininess: %g}", p.diffuse, p.specular, p.shininess)
+}
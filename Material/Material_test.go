@@ -0,0 +1,81 @@
+package Material
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFresnel(t *testing.T) {
+	tests := []struct {
+		name           string
+		cosIncident    float64
+		etaIncident    float64
+		etaTransmitted float64
+		reflectance    float64
+	}{
+		{
+			name:           "normal incidence on glass",
+			cosIncident:    1,
+			etaIncident:    1,
+			etaTransmitted: 1.5,
+			reflectance:    0.04,
+		},
+		{
+			name:           "normal incidence leaving glass",
+			cosIncident:    1,
+			etaIncident:    1.5,
+			etaTransmitted: 1,
+			reflectance:    0.04,
+		},
+		{
+			name:           "normal incidence on water",
+			cosIncident:    1,
+			etaIncident:    1,
+			etaTransmitted: 1.33,
+			reflectance:    (0.33 / 2.33) * (0.33 / 2.33),
+		},
+		{
+			name:           "grazing",
+			cosIncident:    0,
+			etaIncident:    1,
+			etaTransmitted: 1.5,
+			reflectance:    1,
+		},
+		{
+			name:           "total internal reflection at 45 degrees",
+			cosIncident:    math.Cos(math.Pi / 4),
+			etaIncident:    1.5,
+			etaTransmitted: 1,
+			reflectance:    1,
+		},
+		{
+			name:           "45 degrees onto glass",
+			cosIncident:    math.Cos(math.Pi / 4),
+			etaIncident:    1,
+			etaTransmitted: 1.5,
+			reflectance:    0.0502,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fresnel(tt.cosIncident, tt.etaIncident, tt.etaTransmitted)
+			if math.Abs(got-tt.reflectance) > 0.0001 {
+				t.Errorf("Fresnel() = %v, want %v", got, tt.reflectance)
+			}
+		})
+	}
+}
+
+func TestFresnelIsReciprocal(t *testing.T) {
+	for _, angle := range []float64{0.1, 0.3, 0.6, 0.9, 1.2} {
+		sinTransmitted := math.Sin(angle) / 1.5
+		cosTransmitted := math.Sqrt(1 - sinTransmitted*sinTransmitted)
+
+		entering := Fresnel(math.Cos(angle), 1, 1.5)
+		leaving := Fresnel(cosTransmitted, 1.5, 1)
+		if math.Abs(entering-leaving) > 0.0000001 {
+			t.Errorf("Angle %g: entering reflects %v but leaving reflects %v", angle, entering, leaving)
+		}
+	}
+}
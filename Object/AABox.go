@@ -0,0 +1,195 @@
+package Object
+
+import (
+	"fmt"
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+)
+
+// AABox is a box aligned with the world axes, spanning min to max
+type AABox struct {
+	surface
+	min Vector.Vector
+	max Vector.Vector
+}
+
+// NewAABox accepts the two corners in any order
+func NewAABox(corner1, corner2, colorVector Vector.Vector) *AABox {
+	return &AABox{
+		surface: newSurface(colorVector),
+		min:     *Vector.New(math.Min(corner1.X(), corner2.X()), math.Min(corner1.Y(), corner2.Y()), math.Min(corner1.Z(), corner2.Z())),
+		max:     *Vector.New(math.Max(corner1.X(), corner2.X()), math.Max(corner1.Y(), corner2.Y()), math.Max(corner1.Z(), corner2.Z())),
+	}
+}
+
+func (b *AABox) String() string {
+	return fmt.Sprintf("{min: %s, max: %s}", b.min, b.max)
+}
+
+func (b *AABox) GetBounds() Bounds {
+	return Bounds{Min: b.min, Max: b.max}
+}
+
+// GetHitNormal returns the outward normal of the face closest to the hit point
+func (b *AABox) GetHitNormal(ray Ray.Ray, t float64) Vector.Vector {
+	phit := ray.Origin().Translate(ray.Direction().Scale(t))
+
+	faces := []struct {
+		distance float64
+		normal   Vector.Vector
+	}{
+		{math.Abs(phit.X() - b.min.X()), *Vector.New(-1, 0, 0)},
+		{math.Abs(phit.X() - b.max.X()), *Vector.New(1, 0, 0)},
+		{math.Abs(phit.Y() - b.min.Y()), *Vector.New(0, -1, 0)},
+		{math.Abs(phit.Y() - b.max.Y()), *Vector.New(0, 1, 0)},
+		{math.Abs(phit.Z() - b.min.Z()), *Vector.New(0, 0, -1)},
+		{math.Abs(phit.Z() - b.max.Z()), *Vector.New(0, 0, 1)},
+	}
+
+	closest := faces[0]
+	for _, face := range faces[1:] {
+		if face.distance < closest.distance {
+			closest = face
+		}
+	}
+	return closest.normal
+}
+
+// GetUV spans each face from 0 to 1, v running up (-y) on the side faces and along
+// +z on the top and bottom
+func (b *AABox) GetUV(ray Ray.Ray, t float64) UV {
+	phit := ray.Origin().Translate(ray.Direction().Scale(t))
+	normal := b.GetHitNormal(ray, t)
+
+	across := func(value, low, high float64) float64 {
+		if high == low {
+			return 0
+		}
+		return (value - low) / (high - low)
+	}
+	x := across(phit.X(), b.min.X(), b.max.X())
+	up := across(phit.Y(), b.max.Y(), b.min.Y())
+	z := across(phit.Z(), b.min.Z(), b.max.Z())
+
+	switch {
+	case normal.X() != 0:
+		return UV{U: z, V: up}
+	case normal.Y() != 0:
+		return UV{U: x, V: z}
+	}
+	return UV{U: x, V: up}
+}
+
+// GetTangents follows GetUV on the face the ray hit, u and v each running across the
+// whole face
+func (b *AABox) GetTangents(ray Ray.Ray, t float64) (Vector.Vector, Vector.Vector) {
+	normal := b.GetHitNormal(ray, t)
+	size := b.max.Minus(b.min)
+	up := *Vector.New(0, -size.Y(), 0)
+	switch {
+	case normal.X() != 0:
+		return *Vector.New(0, 0, size.Z()), up
+	case normal.Y() != 0:
+		return *Vector.New(size.X(), 0, 0), *Vector.New(0, 0, size.Z())
+	}
+	return *Vector.New(size.X(), 0, 0), up
+}
+
+func (b *AABox) Area() float64 {
+	size := b.max.Minus(b.min)
+	return 2 * (size.Y()*size.Z() + size.Z()*size.X() + size.X()*size.Y())
+}
+
+// SampleSurface picks a face by its share of the area with u and spreads what is left
+// of u and v evenly over it, returning the point with the face's outward normal
+func (b *AABox) SampleSurface(u, v float64) (Vector.Vector, Vector.Vector) {
+	low := [3]float64{b.min.X(), b.min.Y(), b.min.Z()}
+	high := [3]float64{b.max.X(), b.max.Y(), b.max.Z()}
+	size := [3]float64{high[0] - low[0], high[1] - low[1], high[2] - low[2]}
+
+	// the two faces across each axis share half of the area spanned by the other two
+	var areas [3]float64
+	for axis := range areas {
+		areas[axis] = size[(axis+1)%3] * size[(axis+2)%3]
+	}
+	pick := u * (areas[0] + areas[1] + areas[2])
+	axis := 0
+	for axis < 2 && (pick >= areas[axis] || areas[axis] == 0) {
+		pick -= areas[axis]
+		axis++
+	}
+	along := 0.0
+	if areas[axis] > 0 {
+		along = math.Min(1, pick/areas[axis])
+	}
+
+	point := low
+	var normal [3]float64
+	if along < 0.5 {
+		normal[axis] = -1
+		along *= 2
+	} else {
+		point[axis] = high[axis]
+		normal[axis] = 1
+		along = 2*along - 1
+	}
+	point[(axis+1)%3] += along * size[(axis+1)%3]
+	point[(axis+2)%3] += v * size[(axis+2)%3]
+	return *Vector.New(point[0], point[1], point[2]), *Vector.New(normal[0], normal[1], normal[2])
+}
+
+// IntersectDistance uses the slab method, rays starting inside the box hit its far side
+func (b *AABox) IntersectDistance(r Ray.Ray) (bool, float64) {
+	tNear, tFar, ok := b.slabs(r)
+	if !ok || tFar < 0 {
+		return false, 0
+	}
+	if tNear < 0 {
+		return true, tFar
+	}
+	return true, tNear
+}
+
+func (b *AABox) slabs(r Ray.Ray) (float64, float64, bool) {
+	origin := r.Origin()
+	direction := r.Direction()
+
+	tNear := math.Inf(-1)
+	tFar := math.Inf(1)
+	axes := [3][4]float64{
+		{origin.X(), direction.X(), b.min.X(), b.max.X()},
+		{origin.Y(), direction.Y(), b.min.Y(), b.max.Y()},
+		{origin.Z(), direction.Z(), b.min.Z(), b.max.Z()},
+	}
+
+	for _, axis := range axes {
+		o, d, lo, hi := axis[0], axis[1], axis[2], axis[3]
+		if d == 0 {
+			if o < lo || o > hi {
+				return 0, 0, false
+			}
+			continue
+		}
+
+		t0 := (lo - o) / d
+		t1 := (hi - o) / d
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		tNear = math.Max(tNear, t0)
+		tFar = math.Min(tFar, t1)
+		if tNear > tFar {
+			return 0, 0, false
+		}
+	}
+	return tNear, tFar, true
+}
+
+func (b *AABox) Min() Vector.Vector {
+	return b.min
+}
+
+func (b *AABox) Max() Vector.Vector {
+	return b.max
+}
@@ -0,0 +1,125 @@
+package Object
+
+import (
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+func TestAABoxIntersectDistance(t *testing.T) {
+	origin := Vector.Vector{}
+	box := NewAABox(*Vector.New(5, 5, 60), *Vector.New(-5, -5, 40), white)
+
+	tests := []struct {
+		ray        Ray.Ray
+		intersects bool
+		t          float64
+	}{
+		{
+			ray:        Ray.New(origin, *Vector.New(0, 0, 1)),
+			intersects: true,
+			t:          40,
+		},
+		{
+			ray:        Ray.New(origin, *Vector.New(0, 0, -1)),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(*Vector.New(6, 0, 0), *Vector.New(0, 0, 1)),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(*Vector.New(5, 0, 0), *Vector.New(0, 0, 1)),
+			intersects: true,
+			t:          40,
+		},
+		{
+			ray:        Ray.New(*Vector.New(0, 0, 50), *Vector.New(1, 0, 0)),
+			intersects: true,
+			t:          5,
+		},
+		{
+			ray:        Ray.New(*Vector.New(-20, 0, 50), Vector.New(1, 1, 0).Normalize()),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(*Vector.New(-10, -10, 50), Vector.New(1, 1, 0).Normalize()),
+			intersects: true,
+			t:          5 * math.Sqrt2,
+		},
+	}
+
+	for i, tt := range tests {
+		intersects, distance := box.IntersectDistance(tt.ray)
+
+		if intersects != tt.intersects {
+			t.Errorf("Test %d: Expected interesection to be '%t', got '%t'", i, tt.intersects, intersects)
+			continue
+		}
+		if intersects && math.Abs(distance-tt.t) > 0.0000000001 {
+			t.Errorf("Test %d: Expected: %g got: %g", i, tt.t, distance)
+		}
+	}
+}
+
+func TestAABoxGetHitNormal(t *testing.T) {
+	box := NewAABox(*Vector.New(-5, -5, 40), *Vector.New(5, 5, 60), white)
+
+	tests := []struct {
+		ray    Ray.Ray
+		normal Vector.Vector
+	}{
+		{
+			ray:    Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1)),
+			normal: *Vector.New(0, 0, -1),
+		},
+		{
+			ray:    Ray.New(*Vector.New(0, 0, 100), *Vector.New(0, 0, -1)),
+			normal: *Vector.New(0, 0, 1),
+		},
+		{
+			ray:    Ray.New(*Vector.New(20, 1, 50), *Vector.New(-1, 0, 0)),
+			normal: *Vector.New(1, 0, 0),
+		},
+		{
+			ray:    Ray.New(*Vector.New(1, -20, 50), *Vector.New(0, 1, 0)),
+			normal: *Vector.New(0, -1, 0),
+		},
+	}
+
+	for i, tt := range tests {
+		intersects, distance := box.IntersectDistance(tt.ray)
+		if !intersects {
+			t.Fatalf("Test %d: Doesn't intersect", i)
+		}
+
+		if res := box.GetHitNormal(tt.ray, distance); res != tt.normal {
+			t.Errorf("Test %d: Incorrect normal, expected %v, got %v", i, tt.normal, res)
+		}
+	}
+}
+
+func TestAABoxGetUV(t *testing.T) {
+	box := NewAABox(*Vector.New(-5, -5, 40), *Vector.New(5, 5, 60), white)
+
+	tests := []struct {
+		ray  Ray.Ray
+		u, v float64
+	}{
+		// the front face, u along +x and v up
+		{ray: Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1)), u: 0.5, v: 0.5},
+		{ray: Ray.New(*Vector.New(-4, 4, 0), *Vector.New(0, 0, 1)), u: 0.1, v: 0.1},
+		// the +x face, u along +z
+		{ray: Ray.New(*Vector.New(100, -3, 55), *Vector.New(-1, 0, 0)), u: 0.75, v: 0.8},
+		// the top face, u along +x and v along +z
+		{ray: Ray.New(*Vector.New(-4, -100, 42), *Vector.New(0, 1, 0)), u: 0.1, v: 0.1},
+	}
+
+	for i, tt := range tests {
+		_, distance := box.IntersectDistance(tt.ray)
+		if uv := box.GetUV(tt.ray, distance); math.Abs(uv.U-tt.u) > 1e-9 || math.Abs(uv.V-tt.v) > 1e-9 {
+			t.Errorf("Test %d: Expected %g, %g, got %g, %g", i, tt.u, tt.v, uv.U, uv.V)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package Object
+
+import (
+	"goRay/Material"
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+	"sort"
+)
+
+// Mesh is a group of triangles rendered as a single object
+type Mesh struct {
+	surface
+	triangles []*Triangle
+	bounds    *AABox
+	// areas holds the area of the triangles up to and including each one
+	areas []float64
+}
+
+func NewMesh(triangles []*Triangle, colorVector Vector.Vector) *Mesh {
+	mesh := &Mesh{
+		surface:   newSurface(colorVector),
+		triangles: triangles,
+	}
+	for _, triangle := range triangles {
+		triangle.SetMaterial(mesh.material)
+	}
+	if len(triangles) > 0 {
+		bounds := EmptyBounds()
+		for _, triangle := range triangles {
+			bounds = bounds.Union(triangle.GetBounds())
+		}
+		mesh.bounds = NewAABox(bounds.Min, bounds.Max, Vector.Vector{})
+	}
+	total := 0.0
+	for _, triangle := range triangles {
+		total += triangle.Area()
+		mesh.areas = append(mesh.areas, total)
+	}
+	return mesh
+}
+
+func (m *Mesh) Triangles() []*Triangle {
+	return m.triangles
+}
+
+// Primitives lets acceleration structures index the triangles one by one
+func (m *Mesh) Primitives() []Object {
+	primitives := make([]Object, len(m.triangles))
+	for i, triangle := range m.triangles {
+		primitives[i] = triangle
+	}
+	return primitives
+}
+
+func (m *Mesh) Area() float64 {
+	if len(m.areas) == 0 {
+		return 0
+	}
+	return m.areas[len(m.areas)-1]
+}
+
+// SampleSurface picks a triangle by its share of the area with u and spreads what is
+// left of u and v evenly over it, so the whole mesh glows as one light
+func (m *Mesh) SampleSurface(u, v float64) (Vector.Vector, Vector.Vector) {
+	if m.Area() == 0 {
+		return Vector.Vector{}, Vector.Vector{}
+	}
+
+	// the first triangle ending past pick, which skips triangles without area
+	pick := u * m.Area()
+	i := sort.Search(len(m.areas), func(i int) bool { return m.areas[i] > pick })
+	if i == len(m.areas) {
+		i = sort.SearchFloat64s(m.areas, m.Area())
+	}
+	start := 0.0
+	if i > 0 {
+		start = m.areas[i-1]
+	}
+	along := math.Min(1, (pick-start)/(m.areas[i]-start))
+	return m.triangles[i].SampleSurface(along, v)
+}
+
+func (m *Mesh) GetBounds() Bounds {
+	if m.bounds == nil {
+		return EmptyBounds()
+	}
+	return m.bounds.GetBounds()
+}
+
+// SetMaterial applies the material to every triangle of the mesh
+func (m *Mesh) SetMaterial(material Material.Material) {
+	m.material = material
+	for _, triangle := range m.triangles {
+		triangle.SetMaterial(material)
+	}
+}
+
+func (m *Mesh) IntersectDistance(r Ray.Ray) (bool, float64) {
+	triangle, t := m.closest(r)
+	return triangle != nil, t
+}
+
+// GetHitNormal finds the triangle the ray hit at t and returns its normal
+func (m *Mesh) GetHitNormal(ray Ray.Ray, t float64) Vector.Vector {
+	triangle, _ := m.closest(ray)
+	if triangle == nil {
+		return Vector.Vector{}
+	}
+	return triangle.GetHitNormal(ray, t)
+}
+
+// GetUV finds the triangle the ray hit at t and returns its texture coordinates
+func (m *Mesh) GetUV(ray Ray.Ray, t float64) UV {
+	triangle, _ := m.closest(ray)
+	if triangle == nil {
+		return UV{}
+	}
+	return triangle.GetUV(ray, t)
+}
+
+// GetTangents finds the triangle the ray hit at t and returns its tangents
+func (m *Mesh) GetTangents(ray Ray.Ray, t float64) (Vector.Vector, Vector.Vector) {
+	triangle, _ := m.closest(ray)
+	if triangle == nil {
+		return Vector.Vector{}, Vector.Vector{}
+	}
+	return triangle.GetTangents(ray, t)
+}
+
+func (m *Mesh) closest(r Ray.Ray) (*Triangle, float64) {
+	if m.bounds == nil {
+		return nil, 0
+	}
+	if _, tFar, ok := m.bounds.slabs(r); !ok || tFar < 0 {
+		return nil, 0
+	}
+
+	var closest *Triangle
+	closestT := math.MaxFloat64
+	for _, triangle := range m.triangles {
+		if intersects, t := triangle.IntersectDistance(r); intersects && t < closestT {
+			closest = triangle
+			closestT = t
+		}
+	}
+	return closest, closestT
+}
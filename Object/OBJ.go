@@ -0,0 +1,176 @@
+package Object
+
+import (
+	"bufio"
+	"fmt"
+	"goRay/Vector"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadOBJ reads a Wavefront .obj file into a mesh of the given color
+func LoadOBJ(path string, colorVector Vector.Vector) (*Mesh, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mesh, err := ParseOBJ(file, colorVector)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return mesh, nil
+}
+
+// ParseOBJ reads the v, vn, vt and f records of a Wavefront .obj file. Faces with
+// more than three vertices are split into a triangle fan, negative indices count
+// back from the last record read. Records other than these are ignored.
+func ParseOBJ(r io.Reader, colorVector Vector.Vector) (*Mesh, error) {
+	var vertices []Vector.Vector
+	var normals []Vector.Vector
+	var uvs []UV
+	var triangles []*Triangle
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if comment := strings.IndexByte(line, '#'); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "v":
+			values, err := parseFloats(fields[1:], 3, 4)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: vertex: %w", lineNumber, err)
+			}
+			vertices = append(vertices, *Vector.New(values[0], values[1], values[2]))
+		case "vn":
+			values, err := parseFloats(fields[1:], 3, 3)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: normal: %w", lineNumber, err)
+			}
+			normals = append(normals, *Vector.New(values[0], values[1], values[2]))
+		case "vt":
+			values, err := parseFloats(fields[1:], 1, 3)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: texture coordinate: %w", lineNumber, err)
+			}
+			uv := UV{U: values[0]}
+			if len(values) > 1 {
+				uv.V = values[1]
+			}
+			uvs = append(uvs, uv)
+		case "f":
+			face, err := parseFace(fields[1:], len(vertices), len(uvs), len(normals))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: face: %w", lineNumber, err)
+			}
+			for i := 1; i+1 < len(face); i++ {
+				triangles = append(triangles, face.triangle(0, i, i+1, vertices, uvs, normals, colorVector))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
+	}
+
+	return NewMesh(triangles, colorVector), nil
+}
+
+func parseFloats(fields []string, min, max int) ([]float64, error) {
+	if len(fields) < min || len(fields) > max {
+		if min == max {
+			return nil, fmt.Errorf("expected %d values, got %d", min, len(fields))
+		}
+		return nil, fmt.Errorf("expected %d to %d values, got %d", min, max, len(fields))
+	}
+
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", field)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// faceVertex holds zero based indices, -1 when the record left them out
+type faceVertex struct {
+	vertex int
+	uv     int
+	normal int
+}
+
+type face []faceVertex
+
+func parseFace(fields []string, vertexCount, uvCount, normalCount int) (face, error) {
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("expected at least 3 vertices, got %d", len(fields))
+	}
+
+	f := make(face, len(fields))
+	for i, field := range fields {
+		parts := strings.Split(field, "/")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid vertex %q", field)
+		}
+
+		var err error
+		fv := faceVertex{vertex: -1, uv: -1, normal: -1}
+		if fv.vertex, err = resolveIndex(parts[0], vertexCount, "vertex"); err != nil {
+			return nil, err
+		}
+		if len(parts) > 1 && parts[1] != "" {
+			if fv.uv, err = resolveIndex(parts[1], uvCount, "texture coordinate"); err != nil {
+				return nil, err
+			}
+		}
+		if len(parts) > 2 && parts[2] != "" {
+			if fv.normal, err = resolveIndex(parts[2], normalCount, "normal"); err != nil {
+				return nil, err
+			}
+		}
+		f[i] = fv
+	}
+	return f, nil
+}
+
+// resolveIndex turns a one based or negative relative index into a zero based one
+func resolveIndex(field string, count int, kind string) (int, error) {
+	index, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s index %q", kind, field)
+	}
+
+	resolved := index - 1
+	if index < 0 {
+		resolved = count + index
+	}
+	if index == 0 || resolved < 0 || resolved >= count {
+		return 0, fmt.Errorf("%s index %d out of range, %d defined so far", kind, index, count)
+	}
+	return resolved, nil
+}
+
+func (f face) triangle(a, b, c int, vertices []Vector.Vector, uvs []UV, normals []Vector.Vector, colorVector Vector.Vector) *Triangle {
+	triangle := NewTriangle(vertices[f[a].vertex], vertices[f[b].vertex], vertices[f[c].vertex], colorVector)
+	if f[a].normal >= 0 && f[b].normal >= 0 && f[c].normal >= 0 {
+		triangle.SetVertexNormals(normals[f[a].normal], normals[f[b].normal], normals[f[c].normal])
+	}
+	if f[a].uv >= 0 && f[b].uv >= 0 && f[c].uv >= 0 {
+		triangle.SetTextureCoordinates(uvs[f[a].uv], uvs[f[b].uv], uvs[f[c].uv])
+	}
+	return triangle
+}
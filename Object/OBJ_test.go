@@ -0,0 +1,120 @@
+package Object
+
+import (
+	"goRay/Ray"
+	"goRay/Vector"
+	"strings"
+	"testing"
+)
+
+const cubeOBJ = `# unit cube made of quads
+o cube
+v -1 -1 -1
+v  1 -1 -1
+v  1  1 -1
+v -1  1 -1
+v -1 -1  1
+v  1 -1  1
+v  1  1  1
+v -1  1  1
+vn 0 0 -1
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+f 1/1/1 4/4/1 3/3/1 2/2/1
+f 5 6 7 8
+f 1//1 5//1 8//1 4//1
+f 2 3 7 6
+f 4 8 7 3
+f 1 2 6 5
+`
+
+func TestParseOBJ(t *testing.T) {
+	mesh, err := ParseOBJ(strings.NewReader(cubeOBJ), white)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mesh.Triangles()) != 12 {
+		t.Fatalf("Expected 12 triangles, got %d", len(mesh.Triangles()))
+	}
+
+	first := mesh.Triangles()[0]
+	if !first.hasNormals || !first.hasUVs {
+		t.Errorf("Expected the first face to carry normals and texture coordinates")
+	}
+	if mesh.Triangles()[2].hasNormals || mesh.Triangles()[2].hasUVs {
+		t.Errorf("Expected the second face to have neither normals nor texture coordinates")
+	}
+
+	ray := Ray.New(*Vector.New(0, 0, -10), *Vector.New(0, 0, 1))
+	intersects, distance := mesh.IntersectDistance(ray)
+	if !intersects || distance != 9 {
+		t.Errorf("Expected to hit the cube at 9, got %t %g", intersects, distance)
+	}
+}
+
+func TestParseOBJNegativeIndices(t *testing.T) {
+	obj := `v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+f -4 -3 -2 -1
+`
+	mesh, err := ParseOBJ(strings.NewReader(obj), white)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mesh.Triangles()) != 2 {
+		t.Fatalf("Expected 2 triangles, got %d", len(mesh.Triangles()))
+	}
+
+	v0, v1, v2 := mesh.Triangles()[1].Vertices()
+	if v0 != *Vector.New(0, 0, 0) || v1 != *Vector.New(1, 1, 0) || v2 != *Vector.New(0, 1, 0) {
+		t.Errorf("Expected the fan to reuse the first vertex, got %v %v %v", v0, v1, v2)
+	}
+}
+
+func TestParseOBJErrors(t *testing.T) {
+	tests := []struct {
+		obj   string
+		error string
+	}{
+		{
+			obj:   "v 0 0 0\nv 1 zero 0\n",
+			error: "line 2: vertex: invalid number \"zero\"",
+		},
+		{
+			obj:   "v 0 0 0\nv 1 0 0\n\nf 1 2\n",
+			error: "line 4: face: expected at least 3 vertices, got 2",
+		},
+		{
+			obj:   "v 0 0 0\nv 1 0 0\nv 1 1 0\nf 1 2 4\n",
+			error: "line 4: face: vertex index 4 out of range, 3 defined so far",
+		},
+		{
+			obj:   "v 0 0 0\nv 1 0 0\nv 1 1 0\nf 1 2 -4\n",
+			error: "line 4: face: vertex index -4 out of range, 3 defined so far",
+		},
+		{
+			obj:   "v 0 0 0\nv 1 0 0\nv 1 1 0\nf 1//1 2//1 3//1\n",
+			error: "line 4: face: normal index 1 out of range, 0 defined so far",
+		},
+		{
+			obj:   "vn 0 1\n",
+			error: "line 1: normal: expected 3 values, got 2",
+		},
+	}
+
+	for i, tt := range tests {
+		_, err := ParseOBJ(strings.NewReader(tt.obj), white)
+		if err == nil {
+			t.Errorf("Test %d: Expected an error", i)
+			continue
+		}
+		if err.Error() != tt.error {
+			t.Errorf("Test %d: Expected error %q, got %q", i, tt.error, err)
+		}
+	}
+}
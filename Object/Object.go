@@ -1,14 +1,187 @@
 package Object
 
 import (
-	"github.com/veandco/go-sdl2/sdl"
+	"goRay/Material"
 	"goRay/Ray"
+	"goRay/Texture"
 	"goRay/Vector"
+	"math"
 )
 
 type Object interface {
 	IntersectDistance(ray Ray.Ray) (bool, float64)
-	Draw(renderer *sdl.Renderer, xOffset, yOffset int32)
 	GetHitNormal(ray Ray.Ray, t float64) Vector.Vector
+	// GetSurfaceColor is the diffuse color of the object's material
 	GetSurfaceColor() Vector.Vector
+	GetMaterial() Material.Material
+	GetBounds() Bounds
+}
+
+// UVMapper is implemented by objects that lay texture coordinates over their surface
+type UVMapper interface {
+	// GetUV returns the texture coordinates of the hit point t along ray
+	GetUV(ray Ray.Ray, t float64) UV
+}
+
+// GetSurfaceColorAt is the diffuse color of the object at the hit point t along ray,
+// taken from its material's texture when it has one. Objects without texture
+// coordinates look textures up at 0, 0, which only solid textures vary over.
+func GetSurfaceColorAt(object Object, ray Ray.Ray, t float64) Vector.Vector {
+	material := object.GetMaterial()
+	textured, ok := material.(Material.Textured)
+	if !ok || textured.Texture() == nil {
+		return material.Diffuse()
+	}
+
+	var uv UV
+	if mapper, ok := object.(UVMapper); ok {
+		uv = mapper.GetUV(ray, t)
+	}
+	point := ray.Origin().Translate(ray.Direction().Scale(t))
+	return textured.Texture().Evaluate(uv.U, uv.V, point)
+}
+
+// TangentMapper is implemented by objects that know which way their texture
+// coordinates grow, the directions normal and bump maps are laid out along
+type TangentMapper interface {
+	// GetTangents returns how the hit point t along ray moves per unit of u and of v,
+	// they needn't be at right angles. Bump maps are steeper where they are shorter.
+	GetTangents(ray Ray.Ray, t float64) (Vector.Vector, Vector.Vector)
+}
+
+// GetShadingNormal is the normal the object is shaded with at the hit point t along
+// ray, its hit normal bent by its material's normal or bump map when it has one.
+// Objects without tangents get an arbitrary frame around the normal. A normal bent
+// away from the side the ray arrives on is left unbent, it would light the wrong side.
+func GetShadingNormal(object Object, ray Ray.Ray, t float64) Vector.Vector {
+	normal := object.GetHitNormal(ray, t)
+	mapped, ok := object.GetMaterial().(Material.NormalMapped)
+	if !ok || mapped.NormalMap() == nil {
+		return normal
+	}
+
+	var uv UV
+	if mapper, ok := object.(UVMapper); ok {
+		uv = mapper.GetUV(ray, t)
+	}
+	alongU, alongV := normal.OrthonormalBasis()
+	if mapper, ok := object.(TangentMapper); ok {
+		alongU, alongV = mapper.GetTangents(ray, t)
+	}
+	point := ray.Origin().Translate(ray.Direction().Scale(t))
+
+	var shading Vector.Vector
+	if bump, ok := mapped.NormalMap().(Texture.HeightMap); ok {
+		slopeU, slopeV := bump.Slopes(uv.U, uv.V, point)
+		shading = bumpedNormal(normal, alongU, alongV, slopeU, slopeV)
+	} else {
+		tangent, bitangent := orthonormalTangents(normal, alongU, alongV)
+		local := mapped.NormalMap().Normal(uv.U, uv.V, point)
+		shading = tangent.Scale(local.X()).Translate(bitangent.Scale(local.Y())).Translate(normal.Scale(local.Z())).Normalize()
+	}
+
+	toViewer := ray.Direction().Reverse()
+	if (shading.Dot(toViewer) > 0) != (normal.Dot(toViewer) > 0) {
+		return normal
+	}
+	return shading
+}
+
+// bumpedNormal is the normal of the surface raised along normal by a height growing
+// by slopeU per unit of u and slopeV per unit of v, following Blinn. alongU and alongV
+// are how the surface point moves with u and v, so the slopes are steeper over short
+// ones. Where they don't span the surface, such as at the poles of a sphere, a frame
+// of unit tangents is used.
+func bumpedNormal(normal, alongU, alongV Vector.Vector, slopeU, slopeV float64) Vector.Vector {
+	cross := alongU.Cross(alongV)
+	area := math.Sqrt(cross.Dot(cross))
+	if area < 1e-12 {
+		alongU, alongV = normal.OrthonormalBasis()
+		cross = alongU.Cross(alongV)
+		area = 1
+	}
+	// the tilt is mirrored when u and v wind the other way around the normal
+	tilt := normal.Cross(alongV).Scale(slopeU).Minus(normal.Cross(alongU).Scale(slopeV))
+	if cross.Dot(normal) < 0 {
+		tilt = tilt.Reverse()
+	}
+	return normal.Scale(area).Translate(tilt).Normalize()
+}
+
+// orthonormalTangents turns the directions u and v grow in into unit tangents at right
+// angles to each other and to normal, keeping the side v grows on. Where u doesn't
+// grow along the surface, such as at the poles of a sphere, any frame is used.
+func orthonormalTangents(normal, alongU, alongV Vector.Vector) (Vector.Vector, Vector.Vector) {
+	tangent := alongU.Minus(normal.Scale(normal.Dot(alongU)))
+	if tangent.Dot(tangent) < 1e-18 {
+		return normal.OrthonormalBasis()
+	}
+	tangent = tangent.Normalize()
+	bitangent := normal.Cross(tangent)
+	if bitangent.Dot(alongV) < 0 {
+		bitangent = bitangent.Reverse()
+	}
+	return tangent, bitangent
+}
+
+// Aggregate is implemented by objects made out of other objects, such as meshes.
+// Acceleration structures index the primitives rather than the whole.
+type Aggregate interface {
+	Object
+	Primitives() []Object
+}
+
+// Bounds is an axis aligned bounding box. Objects without a finite extent, such
+// as planes, report infinite bounds.
+type Bounds struct {
+	Min Vector.Vector
+	Max Vector.Vector
+}
+
+// EmptyBounds contains nothing, growing it by any point gives the bounds of that point
+func EmptyBounds() Bounds {
+	return Bounds{
+		Min: *Vector.New(math.Inf(1), math.Inf(1), math.Inf(1)),
+		Max: *Vector.New(math.Inf(-1), math.Inf(-1), math.Inf(-1)),
+	}
+}
+
+func InfiniteBounds() Bounds {
+	return Bounds{
+		Min: *Vector.New(math.Inf(-1), math.Inf(-1), math.Inf(-1)),
+		Max: *Vector.New(math.Inf(1), math.Inf(1), math.Inf(1)),
+	}
+}
+
+func (b Bounds) Grow(p Vector.Vector) Bounds {
+	return Bounds{
+		Min: *Vector.New(math.Min(b.Min.X(), p.X()), math.Min(b.Min.Y(), p.Y()), math.Min(b.Min.Z(), p.Z())),
+		Max: *Vector.New(math.Max(b.Max.X(), p.X()), math.Max(b.Max.Y(), p.Y()), math.Max(b.Max.Z(), p.Z())),
+	}
+}
+
+func (b Bounds) Union(other Bounds) Bounds {
+	return b.Grow(other.Min).Grow(other.Max)
+}
+
+func (b Bounds) Centroid() Vector.Vector {
+	return b.Min.Translate(b.Max).Scale(0.5)
+}
+
+// SurfaceArea is zero for empty bounds
+func (b Bounds) SurfaceArea() float64 {
+	dx, dy, dz := b.Max.X()-b.Min.X(), b.Max.Y()-b.Min.Y(), b.Max.Z()-b.Min.Z()
+	if dx < 0 || dy < 0 || dz < 0 {
+		return 0
+	}
+	return 2 * (dx*dy + dy*dz + dz*dx)
+}
+
+func (b Bounds) IsFinite() bool {
+	for _, f := range []float64{b.Min.X(), b.Min.Y(), b.Min.Z(), b.Max.X(), b.Max.Y(), b.Max.Z()} {
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return false
+		}
+	}
+	return true
 }
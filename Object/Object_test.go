@@ -0,0 +1,228 @@
+package Object
+
+import (
+	"goRay/Material"
+	"goRay/Ray"
+	"goRay/Texture"
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+type uvTangentMapper interface {
+	Object
+	UVMapper
+	TangentMapper
+}
+
+func TestGetTangentsFollowUV(t *testing.T) {
+	textured := NewTriangle(*Vector.New(0, 0, 10), *Vector.New(10, 0, 10), *Vector.New(0, 10, 10), white)
+	textured.SetTextureCoordinates(UV{U: 0.5, V: 0}, UV{U: 0.5, V: 1}, UV{U: 0, V: 0})
+
+	tests := []struct {
+		name   string
+		object uvTangentMapper
+		ray    Ray.Ray
+	}{
+		{name: "sphere front", object: NewSphere(*Vector.New(0, 0, 50), white, 10), ray: Ray.New(*Vector.New(3, -4, 0), *Vector.New(0, 0, 1))},
+		{name: "sphere side", object: NewSphere(*Vector.New(0, 0, 50), white, 10), ray: Ray.New(*Vector.New(100, 2, 53), *Vector.New(-1, 0, 0))},
+		{name: "plane from above", object: NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -1, 0), white), ray: Ray.New(*Vector.New(1, 0, 2), Vector.New(0.3, 1, 0.2).Normalize())},
+		{name: "plane from below", object: NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -1, 0), white), ray: Ray.New(*Vector.New(1, 10, 2), *Vector.New(0, -1, 0))},
+		{name: "box front", object: NewAABox(*Vector.New(-5, -5, 40), *Vector.New(5, 5, 60), white), ray: Ray.New(*Vector.New(1, 2, 0), *Vector.New(0, 0, 1))},
+		{name: "box side", object: NewAABox(*Vector.New(-5, -5, 40), *Vector.New(5, 5, 60), white), ray: Ray.New(*Vector.New(100, 2, 45), *Vector.New(-1, 0, 0))},
+		{name: "box top", object: NewAABox(*Vector.New(-5, -5, 40), *Vector.New(5, 5, 60), white), ray: Ray.New(*Vector.New(1, -100, 45), *Vector.New(0, 1, 0))},
+		{name: "triangle", object: NewTriangle(*Vector.New(0, 0, 10), *Vector.New(10, 0, 10), *Vector.New(0, 10, 10), white), ray: Ray.New(*Vector.New(2, 3, 0), *Vector.New(0, 0, 1))},
+		{name: "triangle with coordinates", object: textured, ray: Ray.New(*Vector.New(2, 3, 0), *Vector.New(0, 0, 1))},
+	}
+
+	// a tiny step along each tangent moves the hit point so that only its own texture
+	// coordinate grows
+	const step = 1e-4
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, distance := tt.object.IntersectDistance(tt.ray)
+			uv := tt.object.GetUV(tt.ray, distance)
+			alongU, alongV := tt.object.GetTangents(tt.ray, distance)
+
+			for i, tangent := range []Vector.Vector{alongU, alongV} {
+				moved := Ray.New(tt.ray.Origin().Translate(tangent.Normalize().Scale(step)), tt.ray.Direction())
+				intersects, movedDistance := tt.object.IntersectDistance(moved)
+				if !intersects {
+					t.Fatalf("Expected the moved ray to hit")
+				}
+				movedUV := tt.object.GetUV(moved, movedDistance)
+				grown, other := movedUV.U-uv.U, movedUV.V-uv.V
+				if i == 1 {
+					grown, other = other, grown
+				}
+				if grown <= 0 || math.Abs(other) > 0.01*grown {
+					t.Errorf("Tangent %d: Expected only its coordinate to grow, got %g and %g", i, grown, other)
+				}
+				// the tangent is as long as the surface moves per unit of its coordinate
+				if length := math.Sqrt(tangent.Dot(tangent)); math.Abs(grown*length-step) > 0.01*step {
+					t.Errorf("Tangent %d: Expected %g per unit of distance, got %g", i, 1/length, grown/step)
+				}
+			}
+		})
+	}
+}
+
+func TestGetShadingNormal(t *testing.T) {
+	floor := NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -1, 0), white)
+	ray := Ray.New(Vector.Vector{}, *Vector.New(0, 1, 0))
+	_, distance := floor.IntersectDistance(ray)
+
+	if normal := GetShadingNormal(floor, ray, distance); normal != *Vector.New(0, -1, 0) {
+		t.Errorf("Expected the hit normal without a normal map, got %v", normal)
+	}
+
+	// a bump rising along u tilts the normal back towards -u
+	material := Material.NewDiffuse(white)
+	material.SetNormalMap(Texture.NewBump(slope{}, 1))
+	floor.SetMaterial(material)
+	alongU, _ := floor.GetTangents(ray, distance)
+	expected := Vector.New(0, -1, 0).Minus(alongU).Normalize()
+	if normal := GetShadingNormal(floor, ray, distance); !vectorsClose(normal, expected) {
+		t.Errorf("Expected %v, got %v", expected, normal)
+	}
+
+	// seen from far along u at a grazing angle the tilted normal faces away, so it stays unbent
+	grazing := Ray.New(alongU.Scale(100).Translate(*Vector.New(0, 4.9, 0)), alongU.Reverse().Translate(*Vector.New(0, 0.001, 0)).Normalize())
+	_, distance = floor.IntersectDistance(grazing)
+	if normal := GetShadingNormal(floor, grazing, distance); normal != *Vector.New(0, -1, 0) {
+		t.Errorf("Expected the hit normal when the bent one faces away, got %v", normal)
+	}
+
+	sphere := NewSphere(*Vector.New(0, 0, 50), white, 10)
+	sphere.SetMaterial(material)
+	top := Ray.New(*Vector.New(0, -100, 50), *Vector.New(0, 1, 0))
+	_, distance = sphere.IntersectDistance(top)
+	if normal := GetShadingNormal(sphere, top, distance); math.Abs(normal.Dot(*Vector.New(0, -1, 0))-math.Sqrt(0.5)) > 1e-6 {
+		t.Errorf("Expected the pole of the sphere to tilt by 45 degrees, got %v", normal)
+	}
+}
+
+// slope rises by 1 per unit of u
+type slope struct{}
+
+func (slope) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	return *Vector.New(u, u, u)
+}
+
+// slopeV rises by 1 per unit of v
+type slopeV struct{}
+
+func (slopeV) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	return *Vector.New(v, v, v)
+}
+
+func TestBumpFollowsTheSurface(t *testing.T) {
+	// bumps as high as one unit of u or v is long rise at 45 degrees, however far the
+	// texture is stretched around the object
+	tests := []struct {
+		object Object
+		height Texture.Texture
+		scale  float64
+		ray    Ray.Ray
+	}{
+		// a full turn of u runs around the equator, v half way around the sphere
+		{object: NewSphere(*Vector.New(0, 0, 50), white, 10), height: slope{}, scale: 2 * math.Pi * 10, ray: Ray.New(*Vector.New(0, 0, 0), *Vector.New(0, 0, 1))},
+		{object: NewSphere(*Vector.New(0, 0, 50), white, 10), height: slopeV{}, scale: math.Pi * 10, ray: Ray.New(*Vector.New(0, 0, 0), *Vector.New(0, 0, 1))},
+		{object: NewSphere(*Vector.New(0, 0, 50), white, 3), height: slope{}, scale: 2 * math.Pi * 3, ray: Ray.New(*Vector.New(0, 0, 0), *Vector.New(0, 0, 1))},
+		// away from the equator the circles of latitude are shorter
+		{object: NewSphere(*Vector.New(0, 0, 50), white, 10), height: slope{}, scale: math.Pi * 10, ray: Ray.New(*Vector.New(0, -5*math.Sqrt(3), 0), *Vector.New(0, 0, 1))},
+		// u runs across the 20 deep side of the box, v up its 10 high side
+		{object: NewAABox(*Vector.New(-5, -5, 40), *Vector.New(5, 5, 60), white), height: slope{}, scale: 20, ray: Ray.New(*Vector.New(100, 2, 45), *Vector.New(-1, 0, 0))},
+		{object: NewAABox(*Vector.New(-5, -5, 40), *Vector.New(5, 5, 60), white), height: slopeV{}, scale: 10, ray: Ray.New(*Vector.New(100, 2, 45), *Vector.New(-1, 0, 0))},
+		{object: NewTriangle(*Vector.New(0, 0, 10), *Vector.New(4, 0, 10), *Vector.New(0, 8, 10), white), height: slopeV{}, scale: 8, ray: Ray.New(*Vector.New(1, 1, 0), *Vector.New(0, 0, 1))},
+	}
+
+	for i, tt := range tests {
+		material := Material.NewDiffuse(white)
+		material.SetNormalMap(Texture.NewBump(tt.height, tt.scale))
+		tt.object.(interface{ SetMaterial(Material.Material) }).SetMaterial(material)
+
+		_, distance := tt.object.IntersectDistance(tt.ray)
+		hitNormal := tt.object.GetHitNormal(tt.ray, distance)
+		normal := GetShadingNormal(tt.object, tt.ray, distance)
+		if cosine := normal.Dot(hitNormal); math.Abs(cosine-math.Sqrt(0.5)) > 1e-3 {
+			t.Errorf("Test %d: Expected the normal to tilt by 45 degrees, got %g degrees", i, math.Acos(cosine)*180/math.Pi)
+		}
+
+		// the normal tilts back against the way the height rises
+		alongU, alongV := tt.object.(TangentMapper).GetTangents(tt.ray, distance)
+		rising := alongU
+		if _, ok := tt.height.(slopeV); ok {
+			rising = alongV
+		}
+		if normal.Dot(rising) >= 0 {
+			t.Errorf("Test %d: Expected the normal to lean away from %v, got %v", i, rising, normal)
+		}
+	}
+}
+
+func TestSampleSurface(t *testing.T) {
+	tests := []struct {
+		object interface {
+			Object
+			Area() float64
+			SampleSurface(u, v float64) (Vector.Vector, Vector.Vector)
+		}
+		area   float64
+		center Vector.Vector
+	}{
+		{
+			object: NewSphere(*Vector.New(1, 2, 3), white, 4),
+			area:   64 * math.Pi,
+			center: *Vector.New(1, 2, 3),
+		},
+		{
+			object: NewTriangle(*Vector.New(0, 0, 10), *Vector.New(6, 0, 10), *Vector.New(0, 3, 13), white),
+			area:   9 * math.Sqrt(2),
+			center: *Vector.New(2, 1, 11),
+		},
+		{
+			object: NewAABox(*Vector.New(-1, 0, 2), *Vector.New(3, 1, 4), white),
+			area:   28,
+			center: *Vector.New(1, 0.5, 3),
+		},
+		{
+			// the larger triangle takes two thirds of the points, one without area none
+			object: NewMesh([]*Triangle{
+				NewTriangle(*Vector.New(0, 0, 0), *Vector.New(4, 0, 0), *Vector.New(0, 2, 0), white),
+				NewTriangle(*Vector.New(1, 1, 1), *Vector.New(2, 2, 2), *Vector.New(3, 3, 3), white),
+				NewTriangle(*Vector.New(0, 0, 5), *Vector.New(2, 0, 5), *Vector.New(0, 2, 5), white),
+			}, white),
+			area:   6,
+			center: *Vector.New(10.0/9, 2.0/3, 5.0/3),
+		},
+	}
+
+	const gridSize = 64
+	for i, tt := range tests {
+		if area := tt.object.Area(); math.Abs(area-tt.area) > 1e-9 {
+			t.Errorf("Test %d: Expected an area of %g, got %g", i, tt.area, area)
+		}
+
+		var sum Vector.Vector
+		for x := 0; x < gridSize; x++ {
+			for y := 0; y < gridSize; y++ {
+				point, normal := tt.object.SampleSurface((float64(x)+0.5)/gridSize, (float64(y)+0.5)/gridSize)
+				sum = sum.Translate(point)
+
+				// coming back along the normal finds the point with the same normal
+				ray := Ray.New(point.Translate(normal.Scale(0.01)), normal.Reverse())
+				if intersects, distance := tt.object.IntersectDistance(ray); !intersects || math.Abs(distance-0.01) > 1e-9 {
+					t.Errorf("Test %d: Expected %v to be on the surface, got %t %g", i, point, intersects, distance)
+				} else if hitNormal := tt.object.GetHitNormal(ray, distance); !vectorsClose(hitNormal, normal) {
+					t.Errorf("Test %d: Expected the normal %v at %v, got %v", i, hitNormal, point, normal)
+				}
+			}
+		}
+
+		// points spread evenly over the area average to its center
+		if mean := sum.Scale(1.0 / (gridSize * gridSize)); mean.DistanceBetween(tt.center) > 0.05 {
+			t.Errorf("Test %d: Expected the samples to center on %v, got %v", i, tt.center, mean)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package Object
+
+import (
+	"fmt"
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+)
+
+// planeEpsilon is the smallest ray/normal cosine still treated as a hit, anything
+// flatter runs parallel to the plane
+const planeEpsilon = 1e-9
+
+// Plane is an infinite plane through point, perpendicular to normal
+type Plane struct {
+	surface
+	point  Vector.Vector
+	normal Vector.Vector
+}
+
+func NewPlane(point, normal, colorVector Vector.Vector) *Plane {
+	return &Plane{
+		surface: newSurface(colorVector),
+		point:   point,
+		normal:  normal.Normalize(),
+	}
+}
+
+func (p *Plane) String() string {
+	return fmt.Sprintf("{point: %s, normal: %s}", p.point, p.normal)
+}
+
+// GetHitNormal returns the plane normal facing back towards the ray, planes are two sided
+func (p *Plane) GetHitNormal(ray Ray.Ray, t float64) Vector.Vector {
+	if p.normal.Dot(ray.Direction()) > 0 {
+		return p.normal.Reverse()
+	}
+	return p.normal
+}
+
+// GetUV measures the hit point from the plane's point along two directions in the
+// plane, so one unit of u or v is one unit of distance
+func (p *Plane) GetUV(ray Ray.Ray, t float64) UV {
+	offset := ray.Origin().Translate(ray.Direction().Scale(t)).Minus(p.point)
+	tangent, bitangent := p.normal.OrthonormalBasis()
+	return UV{U: offset.Dot(tangent), V: offset.Dot(bitangent)}
+}
+
+func (p *Plane) GetTangents(ray Ray.Ray, t float64) (Vector.Vector, Vector.Vector) {
+	return p.normal.OrthonormalBasis()
+}
+
+func (p *Plane) GetBounds() Bounds {
+	return InfiniteBounds()
+}
+
+func (p *Plane) IntersectDistance(r Ray.Ray) (bool, float64) {
+	denominator := p.normal.Dot(r.Direction())
+	if math.Abs(denominator) < planeEpsilon {
+		return false, 0
+	}
+
+	t := p.point.Minus(*r.Origin()).Dot(p.normal) / denominator
+	if t < 0 {
+		return false, 0
+	}
+	return true, t
+}
+
+func (p *Plane) Point() Vector.Vector {
+	return p.point
+}
+
+func (p *Plane) Normal() Vector.Vector {
+	return p.normal
+}
@@ -0,0 +1,139 @@
+package Object
+
+import (
+	"goRay/Material"
+	"goRay/Ray"
+	"goRay/Texture"
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+func TestPlaneIntersectDistance(t *testing.T) {
+	origin := Vector.Vector{}
+	floor := NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -1, 0), white)
+
+	tests := []struct {
+		ray        Ray.Ray
+		intersects bool
+		t          float64
+	}{
+		{
+			ray:        Ray.New(origin, *Vector.New(0, 1, 0)),
+			intersects: true,
+			t:          5,
+		},
+		{
+			ray:        Ray.New(origin, Vector.New(0, 1, 1).Normalize()),
+			intersects: true,
+			t:          5 * math.Sqrt2,
+		},
+		{
+			ray:        Ray.New(origin, *Vector.New(0, -1, 0)),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(origin, *Vector.New(0, 0, 1)),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(*Vector.New(0, 10, 0), *Vector.New(0, -1, 0)),
+			intersects: true,
+			t:          5,
+		},
+	}
+
+	for i, tt := range tests {
+		intersects, distance := floor.IntersectDistance(tt.ray)
+
+		if intersects != tt.intersects {
+			t.Errorf("Test %d: Expected interesection to be '%t', got '%t'", i, tt.intersects, intersects)
+			continue
+		}
+		if intersects && math.Abs(distance-tt.t) > 0.0000000001 {
+			t.Errorf("Test %d: Expected: %g got: %g", i, tt.t, distance)
+		}
+	}
+}
+
+func TestPlaneGetHitNormal(t *testing.T) {
+	floor := NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -2, 0), white)
+
+	tests := []struct {
+		ray    Ray.Ray
+		normal Vector.Vector
+	}{
+		{
+			ray:    Ray.New(Vector.Vector{}, *Vector.New(0, 1, 0)),
+			normal: *Vector.New(0, -1, 0),
+		},
+		{
+			ray:    Ray.New(*Vector.New(0, 10, 0), *Vector.New(0, -1, 0)),
+			normal: *Vector.New(0, 1, 0),
+		},
+	}
+
+	for i, tt := range tests {
+		_, distance := floor.IntersectDistance(tt.ray)
+
+		if res := floor.GetHitNormal(tt.ray, distance); res != tt.normal {
+			t.Errorf("Test %d: Incorrect normal, expected %v, got %v", i, tt.normal, res)
+		}
+	}
+}
+
+func TestPlaneGetUV(t *testing.T) {
+	floor := NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -1, 0), white)
+	down := *Vector.New(0, 1, 0)
+
+	// the plane's point is at 0, 0
+	ray := Ray.New(Vector.Vector{}, down)
+	_, distance := floor.IntersectDistance(ray)
+	if uv := floor.GetUV(ray, distance); math.Abs(uv.U) > 1e-9 || math.Abs(uv.V) > 1e-9 {
+		t.Errorf("Expected 0, 0 at the plane's point, got %g, %g", uv.U, uv.V)
+	}
+
+	// texture coordinates measure distance within the plane
+	tests := []Vector.Vector{*Vector.New(3, 0, 4), *Vector.New(-6, 0, 8), *Vector.New(0, 0, -2)}
+	for i, origin := range tests {
+		ray := Ray.New(origin, down)
+		_, distance := floor.IntersectDistance(ray)
+		uv := floor.GetUV(ray, distance)
+		expected := origin.Dot(origin)
+		if got := uv.U*uv.U + uv.V*uv.V; math.Abs(got-expected) > 1e-9 {
+			t.Errorf("Test %d: Expected a squared distance of %g, got %g", i, expected, got)
+		}
+	}
+}
+
+func TestGetSurfaceColorAt(t *testing.T) {
+	floor := NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -1, 0), *Vector.New(0.5, 0.5, 0.5))
+	ray := Ray.New(Vector.Vector{}, *Vector.New(0, 1, 0))
+	_, distance := floor.IntersectDistance(ray)
+
+	if color := GetSurfaceColorAt(floor, ray, distance); color != *Vector.New(0.5, 0.5, 0.5) {
+		t.Errorf("Expected the diffuse color without a texture, got %v", color)
+	}
+
+	black, red := Vector.Vector{}, *Vector.New(1, 0, 0)
+	material := Material.NewDiffuse(white)
+	material.SetTexture(Texture.NewChecker(red, black, 0.1))
+	floor.SetMaterial(material)
+
+	// squares are 10 units wide, the plane's point sits in a corner of one
+	colors := map[Vector.Vector]bool{}
+	for _, origin := range []Vector.Vector{*Vector.New(5, 0, 5), *Vector.New(-5, 0, 5), *Vector.New(5, 0, -5), *Vector.New(-5, 0, -5)} {
+		ray := Ray.New(origin, *Vector.New(0, 1, 0))
+		_, distance := floor.IntersectDistance(ray)
+		colors[GetSurfaceColorAt(floor, ray, distance)] = true
+	}
+	if !colors[red] || !colors[black] || len(colors) != 2 {
+		t.Errorf("Expected the checker's two colors around the plane's point, got %v", colors)
+	}
+
+	glass := NewPlane(*Vector.New(0, 5, 0), *Vector.New(0, -1, 0), white)
+	glass.SetMaterial(Material.NewDielectric(1.5, red))
+	if color := GetSurfaceColorAt(glass, ray, distance); color != glass.GetMaterial().Diffuse() {
+		t.Errorf("Expected materials without textures to give their diffuse color, got %v", color)
+	}
+}
@@ -2,36 +2,73 @@ package Object
 
 import (
 	"fmt"
-	"github.com/veandco/go-sdl2/sdl"
 	"goRay/Ray"
 	"goRay/Vector"
 	"math"
 )
 
 type Sphere struct {
+	surface
 	center Vector.Vector
-	color  Vector.Vector
 	radius int
 }
 
-func (s *Sphere) GetSurfaceColor() Vector.Vector {
-	return s.color
-}
-
 func (s *Sphere) GetHitNormal(ray Ray.Ray, t float64) Vector.Vector {
 	phit := ray.Origin().Translate(ray.Direction().Scale(t))
 	return phit.Minus(s.center).Normalize()
 }
 
-func (s *Sphere) Draw(renderer *sdl.Renderer, xOffset, yOffset int32) {
-	DrawCircle(renderer, int32(s.center.X())+xOffset, int32(s.center.Z())+yOffset, int32(s.radius))
+// GetUV wraps u once around the vertical axis, starting and ending behind the sphere
+// (+z) so that u is 0.5 on the side facing -z, and runs v from the bottom (+y) to the top
+func (s *Sphere) GetUV(ray Ray.Ray, t float64) UV {
+	normal := s.GetHitNormal(ray, t)
+	return UV{
+		U: 0.5 + math.Atan2(normal.X(), -normal.Z())/(2*math.Pi),
+		V: math.Acos(math.Max(-1, math.Min(1, normal.Y()))) / math.Pi,
+	}
+}
+
+// GetTangents follows GetUV, u growing eastwards around the vertical axis and v
+// northwards towards the top. A full turn of u runs around the circle of latitude and
+// v runs half way around the sphere. At the poles neither is defined, both are 0.
+func (s *Sphere) GetTangents(ray Ray.Ray, t float64) (Vector.Vector, Vector.Vector) {
+	normal := s.GetHitNormal(ray, t)
+	r := float64(s.radius)
+	// the length of alongU is the radius of the circle of latitude over r
+	alongU := *Vector.New(-normal.Z(), 0, normal.X())
+	latitudeRadius := math.Sqrt(alongU.Dot(alongU))
+	if latitudeRadius == 0 {
+		return Vector.Vector{}, Vector.Vector{}
+	}
+	return alongU.Scale(2 * math.Pi * r), normal.Cross(alongU).Scale(math.Pi * r / latitudeRadius)
+}
+
+func (s *Sphere) Area() float64 {
+	r := float64(s.radius)
+	return 4 * math.Pi * r * r
+}
+
+// SampleSurface spreads u and v evenly over the sphere, u picking the height and v the
+// angle around the vertical axis, and returns the point with its outward normal
+func (s *Sphere) SampleSurface(u, v float64) (Vector.Vector, Vector.Vector) {
+	y := 1 - 2*u
+	ring := math.Sqrt(math.Max(0, 1-y*y))
+	phi := 2 * math.Pi * v
+	normal := *Vector.New(ring*math.Cos(phi), y, ring*math.Sin(phi))
+	return s.center.Translate(normal.Scale(float64(s.radius))), normal
+}
+
+func (s *Sphere) GetBounds() Bounds {
+	r := float64(s.radius)
+	extent := *Vector.New(r, r, r)
+	return Bounds{Min: s.center.Minus(extent), Max: s.center.Translate(extent)}
 }
 
 func NewSphere(center, colorVector Vector.Vector, radius int) *Sphere {
 	return &Sphere{
-		center: center,
-		radius: radius,
-		color:  colorVector,
+		surface: newSurface(colorVector),
+		center:  center,
+		radius:  radius,
 	}
 }
 
@@ -88,38 +125,10 @@ func (s *Sphere) IntersectDistance(r Ray.Ray) (bool, float64) {
 
 }
 
-func (s *Sphere) Radius() int {
-	return s.radius
+func (s *Sphere) Center() Vector.Vector {
+	return s.center
 }
 
-func DrawCircle(renderer *sdl.Renderer, centreX, centreY, radius int32) {
-	x := radius - 1
-	y := int32(0)
-	tx := int32(1)
-	ty := int32(1)
-	error := tx - radius*2
-
-	for x >= y {
-		//  Each of the following renders an octant of the circle
-		renderer.DrawPoint(centreX+x, centreY-y)
-		renderer.DrawPoint(centreX+x, centreY+y)
-		renderer.DrawPoint(centreX-x, centreY-y)
-		renderer.DrawPoint(centreX-x, centreY+y)
-		renderer.DrawPoint(centreX+y, centreY-x)
-		renderer.DrawPoint(centreX+y, centreY+x)
-		renderer.DrawPoint(centreX-y, centreY-x)
-		renderer.DrawPoint(centreX-y, centreY+x)
-
-		if error <= 0 {
-			y++
-			error += ty
-			ty += 2
-		}
-
-		if error > 0 {
-			x--
-			tx += 2
-			error += tx - radius*2
-		}
-	}
+func (s *Sphere) Radius() int {
+	return s.radius
 }
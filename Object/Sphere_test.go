@@ -146,3 +146,31 @@ func testRaySphereIntersection(rayDirection Vector.Vector, spherePosition Vector
 	ray := Ray.New(*origin, rayDirection)
 	return sphere.IntersectDistance(ray)
 }
+
+func TestSphereGetUV(t *testing.T) {
+	sphere := NewSphere(*Vector.New(0, 0, 50), white, 10)
+
+	tests := []struct {
+		ray  Ray.Ray
+		u, v float64
+	}{
+		// the side facing the camera
+		{ray: Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1)), u: 0.5, v: 0.5},
+		// the +x side, a quarter further around
+		{ray: Ray.New(*Vector.New(100, 0, 50), *Vector.New(-1, 0, 0)), u: 0.75, v: 0.5},
+		// the -x side
+		{ray: Ray.New(*Vector.New(-100, 0, 50), *Vector.New(1, 0, 0)), u: 0.25, v: 0.5},
+		// the top
+		{ray: Ray.New(*Vector.New(0, -100, 50), *Vector.New(0, 1, 0)), u: 0.5, v: 1},
+		// the bottom
+		{ray: Ray.New(*Vector.New(0, 100, 50), *Vector.New(0, -1, 0)), u: 0.5, v: 0},
+	}
+
+	for i, tt := range tests {
+		_, distance := sphere.IntersectDistance(tt.ray)
+		uv := sphere.GetUV(tt.ray, distance)
+		if math.Abs(uv.V-tt.v) > 1e-9 || (tt.v != 0 && tt.v != 1 && math.Abs(uv.U-tt.u) > 1e-9) {
+			t.Errorf("Test %d: Expected %g, %g, got %g, %g", i, tt.u, tt.v, uv.U, uv.V)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package Object
+
+import (
+	"goRay/Material"
+	"goRay/Vector"
+)
+
+// surface holds the material for the primitives that embed it
+type surface struct {
+	material Material.Material
+}
+
+func newSurface(colorVector Vector.Vector) surface {
+	return surface{material: Material.NewDiffuse(colorVector)}
+}
+
+func (s *surface) GetSurfaceColor() Vector.Vector {
+	return s.material.Diffuse()
+}
+
+func (s *surface) GetMaterial() Material.Material {
+	return s.material
+}
+
+func (s *surface) SetMaterial(material Material.Material) {
+	s.material = material
+}
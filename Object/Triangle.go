@@ -0,0 +1,185 @@
+package Object
+
+import (
+	"fmt"
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+)
+
+// triangleEpsilon rejects rays running parallel to the triangle
+const triangleEpsilon = 1e-12
+
+// UV is a texture coordinate
+type UV struct {
+	U float64
+	V float64
+}
+
+type Triangle struct {
+	surface
+	v0         Vector.Vector
+	v1         Vector.Vector
+	v2         Vector.Vector
+	normals    [3]Vector.Vector
+	hasNormals bool
+	uvs        [3]UV
+	hasUVs     bool
+}
+
+// NewTriangle creates a flat shaded triangle, counter-clockwise winding faces the viewer
+func NewTriangle(v0, v1, v2, colorVector Vector.Vector) *Triangle {
+	return &Triangle{
+		surface: newSurface(colorVector),
+		v0:      v0,
+		v1:      v1,
+		v2:      v2,
+	}
+}
+
+// SetVertexNormals makes the triangle smooth shaded by interpolating the given normals
+func (tr *Triangle) SetVertexNormals(n0, n1, n2 Vector.Vector) {
+	tr.normals = [3]Vector.Vector{n0.Normalize(), n1.Normalize(), n2.Normalize()}
+	tr.hasNormals = true
+}
+
+func (tr *Triangle) SetTextureCoordinates(uv0, uv1, uv2 UV) {
+	tr.uvs = [3]UV{uv0, uv1, uv2}
+	tr.hasUVs = true
+}
+
+func (tr *Triangle) String() string {
+	return fmt.Sprintf("{v0: %s, v1: %s, v2: %s}", tr.v0, tr.v1, tr.v2)
+}
+
+func (tr *Triangle) Vertices() (Vector.Vector, Vector.Vector, Vector.Vector) {
+	return tr.v0, tr.v1, tr.v2
+}
+
+func (tr *Triangle) GetBounds() Bounds {
+	return EmptyBounds().Grow(tr.v0).Grow(tr.v1).Grow(tr.v2)
+}
+
+// GetHitNormal interpolates the vertex normals when there are any, otherwise it
+// takes the face normal given by the winding order. Triangles are hit from both
+// sides, so like planes the normal is turned to face back towards the ray.
+func (tr *Triangle) GetHitNormal(ray Ray.Ray, t float64) Vector.Vector {
+	normal := tr.faceNormal()
+	if tr.hasNormals {
+		phit := ray.Origin().Translate(ray.Direction().Scale(t))
+		w0, w1, w2 := tr.barycentric(phit)
+		normal = tr.normals[0].Scale(w0).
+			Translate(tr.normals[1].Scale(w1)).
+			Translate(tr.normals[2].Scale(w2)).
+			Normalize()
+	}
+
+	if normal.Dot(ray.Direction()) > 0 {
+		return normal.Reverse()
+	}
+	return normal
+}
+
+// GetUV interpolates the vertex texture coordinates when there are any, otherwise v0,
+// v1 and v2 sit at 0, 0, at 1, 0 and at 0, 1
+func (tr *Triangle) GetUV(ray Ray.Ray, t float64) UV {
+	phit := ray.Origin().Translate(ray.Direction().Scale(t))
+	w0, w1, w2 := tr.barycentric(phit)
+	if !tr.hasUVs {
+		return UV{U: w1, V: w2}
+	}
+	return UV{
+		U: tr.uvs[0].U*w0 + tr.uvs[1].U*w1 + tr.uvs[2].U*w2,
+		V: tr.uvs[0].V*w0 + tr.uvs[1].V*w1 + tr.uvs[2].V*w2,
+	}
+}
+
+// GetTangents solves for how far the hit point moves per unit of u and of v from the
+// edges and their texture coordinates, the edges from v0 themselves without coordinates
+func (tr *Triangle) GetTangents(ray Ray.Ray, t float64) (Vector.Vector, Vector.Vector) {
+	edge1, edge2 := tr.v1.Minus(tr.v0), tr.v2.Minus(tr.v0)
+	if !tr.hasUVs {
+		return edge1, edge2
+	}
+	du1, dv1 := tr.uvs[1].U-tr.uvs[0].U, tr.uvs[1].V-tr.uvs[0].V
+	du2, dv2 := tr.uvs[2].U-tr.uvs[0].U, tr.uvs[2].V-tr.uvs[0].V
+	determinant := du1*dv2 - du2*dv1
+	if determinant == 0 {
+		return edge1, edge2
+	}
+	alongU := edge1.Scale(dv2).Minus(edge2.Scale(dv1)).Scale(1 / determinant)
+	alongV := edge2.Scale(du1).Minus(edge1.Scale(du2)).Scale(1 / determinant)
+	return alongU, alongV
+}
+
+func (tr *Triangle) Area() float64 {
+	cross := tr.v1.Minus(tr.v0).Cross(tr.v2.Minus(tr.v0))
+	return math.Sqrt(cross.Dot(cross)) / 2
+}
+
+// SampleSurface spreads u and v evenly over the triangle and returns the point with
+// the face normal given by the winding order
+func (tr *Triangle) SampleSurface(u, v float64) (Vector.Vector, Vector.Vector) {
+	root := math.Sqrt(u)
+	point := tr.v0.Scale(1 - root).
+		Translate(tr.v1.Scale(root * (1 - v))).
+		Translate(tr.v2.Scale(root * v))
+	return point, tr.faceNormal()
+}
+
+func (tr *Triangle) faceNormal() Vector.Vector {
+	return tr.v1.Minus(tr.v0).Cross(tr.v2.Minus(tr.v0)).Normalize()
+}
+
+// barycentric returns the weights of v0, v1 and v2 for a point on the triangle
+func (tr *Triangle) barycentric(p Vector.Vector) (float64, float64, float64) {
+	edge1 := tr.v1.Minus(tr.v0)
+	edge2 := tr.v2.Minus(tr.v0)
+	toPoint := p.Minus(tr.v0)
+
+	d00 := edge1.Dot(edge1)
+	d01 := edge1.Dot(edge2)
+	d11 := edge2.Dot(edge2)
+	d20 := toPoint.Dot(edge1)
+	d21 := toPoint.Dot(edge2)
+
+	denominator := d00*d11 - d01*d01
+	if denominator == 0 {
+		return 1, 0, 0
+	}
+	w1 := (d11*d20 - d01*d21) / denominator
+	w2 := (d00*d21 - d01*d20) / denominator
+	return 1 - w1 - w2, w1, w2
+}
+
+// IntersectDistance uses the Möller–Trumbore algorithm, triangles are hit from both sides
+func (tr *Triangle) IntersectDistance(r Ray.Ray) (bool, float64) {
+	direction := r.Direction()
+	edge1 := tr.v1.Minus(tr.v0)
+	edge2 := tr.v2.Minus(tr.v0)
+
+	p := direction.Cross(edge2)
+	determinant := edge1.Dot(p)
+	if math.Abs(determinant) < triangleEpsilon {
+		return false, 0
+	}
+	inverse := 1 / determinant
+
+	s := r.Origin().Minus(tr.v0)
+	u := s.Dot(p) * inverse
+	if u < 0 || u > 1 {
+		return false, 0
+	}
+
+	q := s.Cross(edge1)
+	v := direction.Dot(q) * inverse
+	if v < 0 || u+v > 1 {
+		return false, 0
+	}
+
+	t := edge2.Dot(q) * inverse
+	if t < 0 {
+		return false, 0
+	}
+	return true, t
+}
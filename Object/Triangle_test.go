@@ -0,0 +1,146 @@
+package Object
+
+import (
+	"goRay/Ray"
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+func TestTriangleIntersectDistance(t *testing.T) {
+	origin := Vector.Vector{}
+	triangle := NewTriangle(*Vector.New(-1, -1, 10), *Vector.New(1, -1, 10), *Vector.New(0, 1, 10), white)
+
+	tests := []struct {
+		ray        Ray.Ray
+		intersects bool
+		t          float64
+	}{
+		{
+			ray:        Ray.New(origin, *Vector.New(0, 0, 1)),
+			intersects: true,
+			t:          10,
+		},
+		{
+			ray:        Ray.New(*Vector.New(0, 0, 20), *Vector.New(0, 0, -1)),
+			intersects: true,
+			t:          10,
+		},
+		{
+			ray:        Ray.New(origin, *Vector.New(0, 0, -1)),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(*Vector.New(2, 0, 0), *Vector.New(0, 0, 1)),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(origin, *Vector.New(1, 0, 0)),
+			intersects: false,
+		},
+		{
+			ray:        Ray.New(*Vector.New(0, 0, 5), Vector.New(0, 0.1, 1).Normalize()),
+			intersects: true,
+			t:          5 * math.Sqrt(1.01),
+		},
+	}
+
+	for i, tt := range tests {
+		intersects, distance := triangle.IntersectDistance(tt.ray)
+
+		if intersects != tt.intersects {
+			t.Errorf("Test %d: Expected interesection to be '%t', got '%t'", i, tt.intersects, intersects)
+			continue
+		}
+		if intersects && math.Abs(distance-tt.t) > 0.0000000001 {
+			t.Errorf("Test %d: Expected: %g got: %g", i, tt.t, distance)
+		}
+	}
+}
+
+func TestTriangleGetHitNormal(t *testing.T) {
+	ray := Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1))
+	flat := NewTriangle(*Vector.New(-1, -1, 10), *Vector.New(0, 1, 10), *Vector.New(1, -1, 10), white)
+	back := NewTriangle(*Vector.New(-1, -1, 10), *Vector.New(1, -1, 10), *Vector.New(0, 1, 10), white)
+
+	smooth := NewTriangle(*Vector.New(-1, 0, 10), *Vector.New(1, 0, 10), *Vector.New(0, 2, 10), white)
+	smooth.SetVertexNormals(*Vector.New(-1, 0, -1), *Vector.New(1, 0, -1), *Vector.New(0, 0, -1))
+
+	tests := []struct {
+		triangle *Triangle
+		normal   Vector.Vector
+	}{
+		{
+			triangle: flat,
+			normal:   *Vector.New(0, 0, -1),
+		},
+		{
+			triangle: back,
+			normal:   *Vector.New(0, 0, -1),
+		},
+		{
+			triangle: smooth,
+			normal:   *Vector.New(0, 0, -1),
+		},
+	}
+
+	for i, tt := range tests {
+		_, distance := tt.triangle.IntersectDistance(ray)
+
+		res := tt.triangle.GetHitNormal(ray, distance)
+		if !vectorsClose(res, tt.normal) {
+			t.Errorf("Test %d: Incorrect normal, expected %v, got %v", i, tt.normal, res)
+		}
+	}
+
+	offCenter := Ray.New(*Vector.New(0.5, 0, 0), *Vector.New(0, 0, 1))
+	_, distance := smooth.IntersectDistance(offCenter)
+	res := smooth.GetHitNormal(offCenter, distance)
+	if !(res.X() > 0 && res.Z() < 0) {
+		t.Errorf("Expected the interpolated normal to lean towards v1, got %v", res)
+	}
+}
+
+func TestMeshHitsClosestTriangle(t *testing.T) {
+	near := NewTriangle(*Vector.New(-1, -1, 10), *Vector.New(0, 1, 10), *Vector.New(1, -1, 10), white)
+	far := NewTriangle(*Vector.New(-1, -1, 20), *Vector.New(1, -1, 20), *Vector.New(0, 1, 20), white)
+	mesh := NewMesh([]*Triangle{far, near}, white)
+
+	ray := Ray.New(Vector.Vector{}, *Vector.New(0, 0, 1))
+	intersects, distance := mesh.IntersectDistance(ray)
+	if !intersects || distance != 10 {
+		t.Fatalf("Expected to hit the near triangle at 10, got %t %g", intersects, distance)
+	}
+	if normal := mesh.GetHitNormal(ray, distance); !vectorsClose(normal, *Vector.New(0, 0, -1)) {
+		t.Errorf("Expected the near triangle's normal, got %v", normal)
+	}
+
+	miss := Ray.New(*Vector.New(5, 0, 0), *Vector.New(0, 0, 1))
+	if intersects, _ := mesh.IntersectDistance(miss); intersects {
+		t.Errorf("Expected the ray to miss the mesh")
+	}
+}
+
+func vectorsClose(v1, v2 Vector.Vector) bool {
+	return v1.DistanceBetween(v2) < 0.0000001
+}
+
+func TestTriangleGetUV(t *testing.T) {
+	triangle := NewTriangle(*Vector.New(0, 0, 10), *Vector.New(10, 0, 10), *Vector.New(0, 10, 10), white)
+	ray := Ray.New(*Vector.New(2, 3, 0), *Vector.New(0, 0, 1))
+	_, distance := triangle.IntersectDistance(ray)
+
+	if uv := triangle.GetUV(ray, distance); math.Abs(uv.U-0.2) > 1e-9 || math.Abs(uv.V-0.3) > 1e-9 {
+		t.Errorf("Expected the barycentric coordinates 0.2, 0.3, got %g, %g", uv.U, uv.V)
+	}
+
+	triangle.SetTextureCoordinates(UV{U: 0.5, V: 0.5}, UV{U: 1, V: 0.5}, UV{U: 0.5, V: 1})
+	if uv := triangle.GetUV(ray, distance); math.Abs(uv.U-0.6) > 1e-9 || math.Abs(uv.V-0.65) > 1e-9 {
+		t.Errorf("Expected the interpolated coordinates 0.6, 0.65, got %g, %g", uv.U, uv.V)
+	}
+
+	mesh := NewMesh([]*Triangle{triangle}, white)
+	if uv := mesh.GetUV(ray, distance); math.Abs(uv.U-0.6) > 1e-9 || math.Abs(uv.V-0.65) > 1e-9 {
+		t.Errorf("Expected the mesh to give the triangle's coordinates, got %g, %g", uv.U, uv.V)
+	}
+}
@@ -0,0 +1,248 @@
+package Output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"goRay/Film"
+	"io"
+	"math"
+	"strings"
+)
+
+// EncodeHDR writes the film as a Radiance RGBE (.hdr) image, which keeps values above 1
+// with a shared 8 bit exponent per pixel
+func EncodeHDR(w io.Writer, film *Film.Film) error {
+	width, height := film.Width(), film.Height()
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y %d +X %d\n", height, width); err != nil {
+		return err
+	}
+
+	scanline := make([]byte, width*4)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			copy(scanline[x*4:], toRGBE(film.At(x, y)))
+		}
+		if err := writeRGBEScanline(bw, scanline, width); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// toRGBE stores the color as three 8 bit mantissas sharing the exponent of the brightest channel
+func toRGBE(r, g, b float32) []byte {
+	r, g, b = float32(math.Max(0, float64(r))), float32(math.Max(0, float64(g))), float32(math.Max(0, float64(b)))
+	brightest := math.Max(float64(r), math.Max(float64(g), float64(b)))
+	if brightest < 1e-32 {
+		return []byte{0, 0, 0, 0}
+	}
+	mantissa, exponent := math.Frexp(brightest)
+	scale := mantissa * 256 / brightest
+	return []byte{byte(float64(r) * scale), byte(float64(g) * scale), byte(float64(b) * scale), byte(exponent + 128)}
+}
+
+// writeRGBEScanline uses the run length encoded layout, each channel written separately
+// in uncompressed chunks. Readers only accept that layout for widths from 8 to 32767,
+// other widths are written flat.
+func writeRGBEScanline(w io.Writer, scanline []byte, width int) error {
+	if width < 8 || width > 0x7fff {
+		_, err := w.Write(scanline)
+		return err
+	}
+
+	if _, err := w.Write([]byte{2, 2, byte(width >> 8), byte(width)}); err != nil {
+		return err
+	}
+	channel := make([]byte, 0, 129)
+	for component := 0; component < 4; component++ {
+		for start := 0; start < width; start += 128 {
+			count := min(128, width-start)
+			channel = append(channel[:0], byte(count))
+			for x := start; x < start+count; x++ {
+				channel = append(channel, scanline[x*4+component])
+			}
+			if _, err := w.Write(channel); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DecodeHDR reads a Radiance RGBE (.hdr) image stored top to bottom and left to right,
+// the orientation nearly every writer uses, with flat or run length encoded scanlines
+func DecodeHDR(r io.Reader) (*Film.Film, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("reading hdr header: %w", err)
+	}
+	if !strings.HasPrefix(magic, "#?") {
+		return nil, errors.New("not a radiance hdr image")
+	}
+
+	// the header is a list of variables ended by an empty line, then the resolution
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("reading hdr header: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if format, ok := strings.CutPrefix(line, "FORMAT="); ok && format != "32-bit_rle_rgbe" {
+			return nil, fmt.Errorf("unsupported hdr format %q", format)
+		}
+	}
+	resolution, err := br.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("reading hdr resolution: %w", err)
+	}
+	var width, height int
+	if _, err := fmt.Sscanf(resolution, "-Y %d +X %d", &height, &width); err != nil {
+		return nil, fmt.Errorf("unsupported hdr resolution %q", strings.TrimSpace(resolution))
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid hdr size %dx%d", width, height)
+	}
+
+	film := Film.New(width, height)
+	scanline := make([]byte, width*4)
+	for y := 0; y < height; y++ {
+		if err := readRGBEScanline(br, scanline, width); err != nil {
+			return nil, fmt.Errorf("reading hdr scanline %d: %w", y, err)
+		}
+		for x := 0; x < width; x++ {
+			r, g, b := fromRGBE(scanline[x*4 : x*4+4])
+			film.Set(x, y, r, g, b)
+		}
+	}
+	return film, nil
+}
+
+// fromRGBE turns the shared exponent back into linear floats, taking the middle of the
+// range each 8 bit mantissa stands for
+func fromRGBE(rgbe []byte) (float32, float32, float32) {
+	if rgbe[3] == 0 {
+		return 0, 0, 0
+	}
+	scale := math.Ldexp(1, int(rgbe[3])-128-8)
+	return float32((float64(rgbe[0]) + 0.5) * scale), float32((float64(rgbe[1]) + 0.5) * scale), float32((float64(rgbe[2]) + 0.5) * scale)
+}
+
+// readRGBEScanline reads a flat or run length encoded scanline. Other writers than
+// writeRGBEScanline also use runs, a count above 128 repeats the next byte count-128 times.
+func readRGBEScanline(br *bufio.Reader, scanline []byte, width int) error {
+	start, err := br.Peek(4)
+	if err != nil {
+		return err
+	}
+	if width < 8 || width > 0x7fff || start[0] != 2 || start[1] != 2 || start[2]&0x80 != 0 {
+		_, err := io.ReadFull(br, scanline)
+		return err
+	}
+	if int(start[2])<<8|int(start[3]) != width {
+		return errors.New("scanline width doesn't match the image")
+	}
+	if _, err := br.Discard(4); err != nil {
+		return err
+	}
+
+	for component := 0; component < 4; component++ {
+		for x := 0; x < width; {
+			count, err := br.ReadByte()
+			if err != nil {
+				return err
+			}
+			run := count > 128
+			if run {
+				count -= 128
+			}
+			if count == 0 || x+int(count) > width {
+				return errors.New("bad run length")
+			}
+
+			value, err := br.ReadByte()
+			for end := x + int(count); x < end; x++ {
+				if err != nil {
+					return err
+				}
+				scanline[x*4+component] = value
+				if !run && x+1 < end {
+					value, err = br.ReadByte()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeEXR writes the film as an uncompressed OpenEXR image with 32 bit float channels
+func EncodeEXR(w io.Writer, film *Film.Film) error {
+	width, height := film.Width(), film.Height()
+
+	var header bytes.Buffer
+	write := func(values ...any) {
+		for _, value := range values {
+			if text, ok := value.(string); ok {
+				header.WriteString(text)
+				header.WriteByte(0)
+				continue
+			}
+			_ = binary.Write(&header, binary.LittleEndian, value)
+		}
+	}
+
+	// magic number and version 2, single part scanline file
+	write(int32(20000630), int32(2))
+
+	// channels are stored in alphabetical order
+	channels := []string{"B", "G", "R"}
+	write("channels", "chlist", int32(len(channels)*18+1))
+	for _, name := range channels {
+		// pixel type 2 is float, then pLinear, three reserved bytes and the sampling
+		write(name, int32(2), uint8(0), [3]uint8{}, int32(1), int32(1))
+	}
+	header.WriteByte(0)
+
+	write("compression", "compression", int32(1), uint8(0))
+	dataWindow := [4]int32{0, 0, int32(width - 1), int32(height - 1)}
+	write("dataWindow", "box2i", int32(16), dataWindow)
+	write("displayWindow", "box2i", int32(16), dataWindow)
+	write("lineOrder", "lineOrder", int32(1), uint8(0))
+	write("pixelAspectRatio", "float", int32(4), float32(1))
+	write("screenWindowCenter", "v2f", int32(8), [2]float32{0, 0})
+	write("screenWindowWidth", "float", int32(4), float32(1))
+	header.WriteByte(0)
+
+	// the offset table points at every scanline, each holding its y, its size and the channels in turn
+	lineSize := width * len(channels) * 4
+	offset := uint64(header.Len() + height*8)
+	for y := 0; y < height; y++ {
+		write(offset + uint64(y*(8+lineSize)))
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(header.Bytes()); err != nil {
+		return err
+	}
+	line := make([]float32, width*len(channels))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b := film.At(x, y)
+			line[x], line[width+x], line[2*width+x] = b, g, r
+		}
+		if err := binary.Write(bw, binary.LittleEndian, [2]int32{int32(y), int32(lineSize)}); err != nil {
+			return err
+		}
+		if err := binary.Write(bw, binary.LittleEndian, line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,184 @@
+package Output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"goRay/Film"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func testFilm(width, height int) *Film.Film {
+	film := Film.New(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			film.Set(x, y, float32(x)*0.75, float32(y)*3.5+0.01, float32(x+y)/100)
+		}
+	}
+	film.Set(0, 0, 0, 0, 0)
+	film.Set(1, 0, 250, 0.5, 1e-3)
+	return film
+}
+
+func TestEncodeHDRRoundTrip(t *testing.T) {
+	// 3 pixels wide is written flat, 200 uses the run length encoded layout
+	for i, width := range []int{3, 200} {
+		film := testFilm(width, 4)
+
+		var buf bytes.Buffer
+		if err := EncodeHDR(&buf, film); err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := DecodeHDR(&buf)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if decoded.Width() != width || decoded.Height() != 4 {
+			t.Fatalf("Test %d: Expected %dx4, got %dx%d", i+1, width, decoded.Width(), decoded.Height())
+		}
+
+		for y := 0; y < 4; y++ {
+			for x := 0; x < width; x++ {
+				r, g, b := film.At(x, y)
+				gotR, gotG, gotB := decoded.At(x, y)
+				// the mantissas have 8 bits, relative to the brightest channel
+				tolerance := float64(max(r, g, b)) / 128
+				if math.Abs(float64(r-gotR)) > tolerance || math.Abs(float64(g-gotG)) > tolerance || math.Abs(float64(b-gotB)) > tolerance {
+					t.Errorf("Test %d: Pixel %d,%d: expected %v %v %v, got %v %v %v", i+1, x, y, r, g, b, gotR, gotG, gotB)
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeHDRRuns(t *testing.T) {
+	// an 8 pixel scanline, each channel mixing a run with literal bytes
+	header := "#?RGBE\nGAMMA=1\nFORMAT=32-bit_rle_rgbe\n\n-Y 1 +X 8\n"
+	scanline := []byte{2, 2, 0, 8,
+		128 + 8, 128, // red: a run of 8
+		2, 64, 32, 128 + 6, 0, // green: 2 literal bytes then a run of 6
+		128 + 8, 0, // blue: a run of 8 zeros
+		128 + 4, 129, 4, 128, 128, 128, 128, // exponents: a run of 4 then 4 literal bytes
+	}
+
+	film, err := DecodeHDR(bytes.NewReader(append([]byte(header), scanline...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		x       int
+		r, g, b float32
+	}{
+		{x: 0, r: 1.00390625, g: 0.50390625, b: 0.00390625},
+		{x: 1, r: 1.00390625, g: 0.25390625, b: 0.00390625},
+		{x: 2, r: 1.00390625, g: 0.00390625, b: 0.00390625},
+		{x: 4, r: 0.501953125, g: 0.001953125, b: 0.001953125},
+	}
+	for i, tt := range tests {
+		if r, g, b := film.At(tt.x, 0); r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("Test %d: Expected %v %v %v, got %v %v %v", i+1, tt.r, tt.g, tt.b, r, g, b)
+		}
+	}
+}
+
+func TestDecodeHDRErrors(t *testing.T) {
+	tests := []string{
+		"P6\n1 1\n255\n\x00\x00\x00",
+		"#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 1 +X 1\n\x00\x00\x00\x00",
+		"#?RADIANCE\n\n+Y 1 +X 1\n\x00\x00\x00\x00",
+		"#?RADIANCE\n\n-Y 2 +X 1\n\x00\x00\x00\x00",
+		"#?RADIANCE\n\n-Y 1 +X 8\n\x02\x02\x00\x08\x89\x00",
+	}
+	for i, data := range tests {
+		if _, err := DecodeHDR(strings.NewReader(data)); err == nil {
+			t.Errorf("Test %d: Expected an error", i+1)
+		}
+	}
+}
+
+func TestEncodeEXRRoundTrip(t *testing.T) {
+	film := testFilm(5, 3)
+
+	var buf bytes.Buffer
+	if err := EncodeEXR(&buf, film); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeEXR(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			r, g, b := film.At(x, y)
+			gotR, gotG, gotB := decoded.At(x, y)
+			if r != gotR || g != gotG || b != gotB {
+				t.Errorf("Pixel %d,%d: expected %v %v %v, got %v %v %v", x, y, r, g, b, gotR, gotG, gotB)
+			}
+		}
+	}
+}
+
+// decodeEXR reads the subset of OpenEXR files EncodeEXR writes, following the offset table
+func decodeEXR(data []byte) (*Film.Film, error) {
+	reader := bytes.NewReader(data)
+	var magic, version int32
+	_ = binary.Read(reader, binary.LittleEndian, &magic)
+	_ = binary.Read(reader, binary.LittleEndian, &version)
+	if magic != 20000630 || version != 2 {
+		return nil, fmt.Errorf("bad magic %d or version %d", magic, version)
+	}
+
+	var dataWindow [4]int32
+	for {
+		name := readString(reader)
+		if name == "" {
+			break
+		}
+		kind := readString(reader)
+		var size int32
+		_ = binary.Read(reader, binary.LittleEndian, &size)
+		value := make([]byte, size)
+		_, _ = io.ReadFull(reader, value)
+		if name == "dataWindow" && kind == "box2i" {
+			_ = binary.Read(bytes.NewReader(value), binary.LittleEndian, &dataWindow)
+		}
+		if name == "channels" && string(value[:2]) != "B\x00" {
+			return nil, fmt.Errorf("expected B to be the first channel")
+		}
+	}
+
+	width, height := int(dataWindow[2]+1), int(dataWindow[3]+1)
+	offsets := make([]uint64, height)
+	_ = binary.Read(reader, binary.LittleEndian, offsets)
+
+	film := Film.New(width, height)
+	for _, offset := range offsets {
+		line := bytes.NewReader(data[offset:])
+		var header [2]int32
+		_ = binary.Read(line, binary.LittleEndian, &header)
+		values := make([]float32, width*3)
+		if err := binary.Read(line, binary.LittleEndian, values); err != nil {
+			return nil, err
+		}
+		for x := 0; x < width; x++ {
+			film.Set(x, int(header[0]), values[2*width+x], values[width+x], values[x])
+		}
+	}
+	return film, nil
+}
+
+func readString(reader *bytes.Reader) string {
+	var text []byte
+	for {
+		b, err := reader.ReadByte()
+		if err != nil || b == 0 {
+			return string(text)
+		}
+		text = append(text, b)
+	}
+}
@@ -0,0 +1,167 @@
+package Output
+
+import (
+	"bufio"
+	"fmt"
+	"goRay/Camera"
+	"goRay/Film"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Format int
+
+const (
+	PNG Format = iota
+	JPEG
+	PPM
+	HDR
+	EXR
+)
+
+func (f Format) String() string {
+	switch f {
+	case PNG:
+		return "png"
+	case JPEG:
+		return "jpeg"
+	case PPM:
+		return "ppm"
+	case HDR:
+		return "hdr"
+	case EXR:
+		return "exr"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// HighDynamicRange reports whether the format stores linear values beyond 1 instead of display colors
+func (f Format) HighDynamicRange() bool {
+	return f == HDR || f == EXR
+}
+
+// FormatFromPath picks the output format from the file extension
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		return PNG, nil
+	case ".jpg", ".jpeg":
+		return JPEG, nil
+	case ".ppm":
+		return PPM, nil
+	case ".hdr":
+		return HDR, nil
+	case ".exr":
+		return EXR, nil
+	}
+	return 0, fmt.Errorf("unsupported output extension %q (want .png, .jpg, .ppm, .hdr or .exr)", filepath.Ext(path))
+}
+
+// ToImage lays the pixels returned by the camera out into an image of width by height
+func ToImage(pixels []Camera.Pixel, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for _, p := range pixels {
+		if p.Color() == nil {
+			continue
+		}
+		img.Set(p.X(), p.Y(), p.Color())
+	}
+	return img
+}
+
+func Encode(w io.Writer, img image.Image, format Format) error {
+	switch format {
+	case PNG:
+		return png.Encode(w, img)
+	case JPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 95})
+	case PPM:
+		return EncodePPM(w, img)
+	case HDR, EXR:
+		return fmt.Errorf("%v stores linear values, write the film instead of an image", format)
+	}
+	return fmt.Errorf("unknown format %v", format)
+}
+
+// EncodePPM writes the image as a binary (P6) portable pixmap
+func EncodePPM(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "P6\n%d %d\n255\n", bounds.Dx(), bounds.Dy()); err != nil {
+		return err
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if _, err := bw.Write([]byte{c.R, c.G, c.B}); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteFile encodes the image into path using the format implied by its extension
+func WriteFile(path string, img image.Image) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	return writeAtomically(path, func(w io.Writer) error {
+		return Encode(w, img, format)
+	})
+}
+
+// WriteFilm writes the film into path using the format implied by its extension. HDR
+// formats keep the linear values, the others are tone mapped and sRGB encoded.
+func WriteFilm(path string, film *Film.Film, toneMapper Film.ToneMapper) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	return writeAtomically(path, func(w io.Writer) error {
+		switch format {
+		case HDR:
+			return EncodeHDR(w, film)
+		case EXR:
+			return EncodeEXR(w, film)
+		}
+		return Encode(w, film.Image(toneMapper), format)
+	})
+}
+
+// writeAtomically encodes into a temporary file next to path and only renames it over
+// path once it is complete, so a failed encode leaves whatever was there before
+func writeAtomically(path string, encode func(w io.Writer) error) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	fail := func(err error) error {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	if err := encode(file); err != nil {
+		return fail(err)
+	}
+	// temporary files are only readable by their owner
+	if err := file.Chmod(0o644); err != nil {
+		return fail(err)
+	}
+	if err := file.Close(); err != nil {
+		return fail(err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package Output
+
+import (
+	"bytes"
+	"goRay/Camera"
+	"goRay/Object"
+	"goRay/Vector"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		format  Format
+		wantErr bool
+	}{
+		{path: "frame.png", format: PNG},
+		{path: "out/frame.JPG", format: JPEG},
+		{path: "frame.jpeg", format: JPEG},
+		{path: "frame.ppm", format: PPM},
+		{path: "frame.hdr", format: HDR},
+		{path: "frame.EXR", format: EXR},
+		{path: "frame.gif", wantErr: true},
+		{path: "frame", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		format, err := FormatFromPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Test %d: unexpected error state: %v", i, err)
+			continue
+		}
+		if !tt.wantErr && format != tt.format {
+			t.Errorf("Test %d: expected %v, got %v", i, tt.format, format)
+		}
+	}
+}
+
+func TestEncodePPM(t *testing.T) {
+	camera := Camera.New(2, 1, Vector.Vector{})
+	img := ToImage(camera.CastRays(), 2, 1)
+	img.Set(0, 0, color.RGBA{R: 1, G: 2, B: 3, A: 255})
+
+	var buf bytes.Buffer
+	if err := EncodePPM(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	header := "P6\n2 1\n255\n"
+	got := buf.Bytes()
+	if string(got[:len(header)]) != header {
+		t.Fatalf("Expected header %q, got %q", header, got[:len(header)])
+	}
+	if len(got) != len(header)+6 {
+		t.Fatalf("Expected %d bytes, got %d", len(header)+6, len(got))
+	}
+	if !bytes.Equal(got[len(header):len(header)+3], []byte{1, 2, 3}) {
+		t.Errorf("Expected first pixel to be 1 2 3, got %v", got[len(header):len(header)+3])
+	}
+}
+
+func TestToImageRoundTripsThroughPNG(t *testing.T) {
+	camera := Camera.New(4, 3, Vector.Vector{})
+	camera.SetObject(Object.NewSphere(*Vector.New(0, 0, 50), *Vector.New(1, 0, 0), 3))
+	pixels := camera.CastRaysConcurrent()
+	img := ToImage(pixels, 4, 3)
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, img, PNG); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range pixels {
+		want := color.RGBAModel.Convert(p.Color())
+		got := color.RGBAModel.Convert(decoded.At(p.X(), p.Y()))
+		if want != got {
+			t.Errorf("Pixel %d,%d: expected %v, got %v", p.X(), p.Y(), want, got)
+		}
+	}
+}
+
+func TestWriteFileReplacesOnlyWhenComplete(t *testing.T) {
+	dir := t.TempDir()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+
+	// images can't be encoded as hdr, the old file has to survive the failure
+	failing := filepath.Join(dir, "render.hdr")
+	if err := os.WriteFile(failing, []byte("previous render"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(failing, img); err == nil {
+		t.Fatal("Expected an error encoding an image as hdr")
+	}
+	if data, err := os.ReadFile(failing); err != nil || string(data) != "previous render" {
+		t.Errorf("Expected the previous file to be left alone, got %q, %v", data, err)
+	}
+
+	path := filepath.Join(dir, "render.png")
+	if err := WriteFile(path, img); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := png.Decode(file); err != nil {
+		t.Errorf("Expected a complete png, got %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected no temporary files to be left behind, got %v", entries)
+	}
+}
@@ -1,20 +1,34 @@
+//go:build !headless
+
 package Renderer
 
 import (
+	"context"
+	"fmt"
 	"github.com/veandco/go-sdl2/sdl"
 	"goRay/Camera"
-	"goRay/Object"
-	"goRay/Vector"
+	"goRay/DebugView"
+	"goRay/Film"
+	"image/color"
 )
 
+// windowSize is the longest side of the viewer window for images smaller than it
+const windowSize = 600
+
+const windowTitle = "GoTracer"
+
+// idleDelay is how many milliseconds the viewer sleeps between polls while no refined image is waiting
+const idleDelay = 10
+
 func Render(w, h int32, camera Camera.Camera) {
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		panic(err)
 	}
 	defer sdl.Quit()
 
-	window, err := sdl.CreateWindow("GoTracer", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED,
-		600, 600, sdl.WINDOW_SHOWN)
+	pixelScale := getPixelScale(w, h)
+	window, err := sdl.CreateWindow(windowTitle, sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED,
+		w*pixelScale, h*pixelScale, sdl.WINDOW_SHOWN)
 	if err != nil {
 		panic(err)
 	}
@@ -30,33 +44,48 @@ func Render(w, h int32, camera Camera.Camera) {
 		panic(err)
 	}
 
-	stateHasChanged := true
+	// the image starts at one sample per pixel and is refined in the background until
+	// the camera's anti-aliasing count is reached, any input starts over
+	canvas := &sdlCanvas{renderer: renderer}
+	progressive := Camera.NewProgressive(&camera)
+	var progress Camera.Progress
+	camera.SetProgressObserver(func(p Camera.Progress) {
+		progress = p
+	})
+	refining := startRefinement(progressive, &progress)
 	running := true
 	for running {
 
-		if stateHasChanged {
+		select {
+		case finished := <-refining.passes:
 			_ = renderer.SetDrawColor(0, 0, 0, 0)
 			_ = renderer.Clear()
 
-			drawPixels(camera.CastRaysConcurrent(), w, h, renderer)
-
-			drawPrimaryRays(camera, renderer)
-			drawVerticalPrimaryRays(camera, renderer)
+			// the first pass also records the primary rays drawn below
+			drawPixels(finished.pixels, pixelScale, renderer)
 
-			//drawRays(camera, renderer)
+			DebugView.DrawOverlay(canvas, &camera)
 
-			//drawMiniMap(camera.CameraPosition, renderer)
+			//DebugView.DrawRays(canvas, &camera)
 
-			drawObjects(camera.ObjectList, renderer)
+			//DebugView.DrawCameraPosition(canvas, &camera)
 
 			renderer.Present()
 
 			_ = window.UpdateSurface()
 
-			stateHasChanged = false
+			window.SetTitle(fmt.Sprintf("%s - sample %d/%d - %v", windowTitle, finished.samples, progressive.Target(), finished.progress))
+		default:
+			sdl.Delay(idleDelay)
 		}
 
-
+		// the camera is only changed while no refinement is running
+		stateHasChanged := false
+		change := func(move func()) {
+			refining.stop()
+			move()
+			stateHasChanged = true
+		}
 		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 			switch e := event.(type) {
 			case *sdl.QuitEvent:
@@ -65,161 +94,142 @@ func Render(w, h int32, camera Camera.Camera) {
 				break
 			case *sdl.KeyboardEvent:
 				if e.Keysym.Sym == sdl.K_LEFT {
-					camera.DecrementYRotation()
-					stateHasChanged = true
+					change(camera.DecrementYRotation)
 				}
 				if e.Keysym.Sym == sdl.K_RIGHT {
-					camera.IncrementYRotation()
-					stateHasChanged = true
+					change(camera.IncrementYRotation)
 				}
 				if e.Keysym.Sym == sdl.K_UP {
-					camera.IncrementForward()
-					stateHasChanged = true
+					change(camera.IncrementForward)
 				}
 				if e.Keysym.Sym == sdl.K_DOWN {
-					camera.DecrementForward()
-					stateHasChanged = true
+					change(camera.DecrementForward)
+				}
+				if e.Keysym.Sym == sdl.K_PAGEUP {
+					change(camera.IncrementPitch)
+				}
+				if e.Keysym.Sym == sdl.K_PAGEDOWN {
+					change(camera.DecrementPitch)
 				}
 				break
 			}
 		}
+
+		if stateHasChanged {
+			progressive.Reset()
+			refining = startRefinement(progressive, &progress)
+		}
 	}
+	refining.stop()
 }
 
-func drawObjects(objectList []Object.Object, renderer *sdl.Renderer) {
-	for _, object := range objectList {
-		object.Draw(renderer, 100, 100)
-	}
+// pass is a refinement step finished in the background, waiting to be drawn
+type pass struct {
+	pixels   []Camera.Pixel
+	samples  int
+	progress Camera.Progress
 }
 
-func drawRays(camera Camera.Camera, renderer *sdl.Renderer) {
-	for _, vectorList := range camera.ScreenCellMatrix {
-		for _, vector := range vectorList {
-			vector1 := vector.RotateY(camera.YRotation)
-			vector2 := vector1.Translate(camera.CameraPosition)
-			camx, camz := camera.CameraPosition.X(), camera.CameraPosition.Z()
-			drawLine(float32(camx), float32(camz), float32(vector2.X()), float32(vector2.Z()), 100, renderer)
-		}
-	}
+// refinement steps the progressive image on its own goroutine, so input is handled
+// while a pass is traced. Finished passes are handed over on passes.
+type refinement struct {
+	passes chan pass
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
-func drawPrimaryRays(camera Camera.Camera, renderer *sdl.Renderer) {
-	camera.CastRays()
-	for _, ray := range camera.GetPrimaryRays() {
-		//hits := false
-		for _, obj := range camera.ObjectList {
-			intersects, _ := obj.IntersectDistance(ray)
-			if intersects {
-				//hits = true
+// startRefinement steps progressive until it is done or stopped. progress is set by
+// the camera's observer and read once each step is finished.
+func startRefinement(progressive *Camera.Progressive, progress *Camera.Progress) *refinement {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &refinement{passes: make(chan pass, 1), cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		for !progressive.Done() {
+			pixels, err := progressive.StepContext(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case r.passes <- pass{pixels: pixels, samples: progressive.Samples(), progress: *progress}:
+			case <-ctx.Done():
+				return
 			}
 		}
+	}()
+	return r
+}
 
-		startPoint := ray.Origin()
-		direction := ray.Direction()
-		endPoint := startPoint.Translate(direction.Scale(30))
-
-		x1 := startPoint.X()
-		y1 := startPoint.Z()
-		x2 := endPoint.X()
-		y2 := endPoint.Z()
+// stop cancels the pass being traced and waits for the goroutine to return, after
+// which the camera can be changed
+func (r *refinement) stop() {
+	r.cancel()
+	<-r.done
+}
 
-		//if hits {
-		//	drawHitLine(float32(x1), float32(y1), float32(x2), float32(y2), 300, renderer)
-		//} else {
-			drawLine(float32(x1), float32(y1), float32(x2), float32(y2), 100, renderer)
-		//}
+// getPixelScale returns how many window pixels each image pixel covers, keeping the aspect of the image
+func getPixelScale(w, h int32) int32 {
+	longest := w
+	if h > longest {
+		longest = h
+	}
+	if longest >= windowSize {
+		return 1
 	}
+	return windowSize / longest
 }
 
-func drawVerticalPrimaryRays(camera Camera.Camera, renderer *sdl.Renderer) {
-	camera.CastRays()
-	for _, ray := range camera.GetPrimaryRays() {
-		hits := false
-		for _, obj := range camera.ObjectList {
-			intersects, _ := obj.IntersectDistance(ray)
-			if intersects {
-				hits = true
-			}
-		}
-
-		startPoint := ray.Origin()
-		direction := ray.Direction()
-		endPoint := startPoint.Translate(direction.Scale(40))
-
+func drawPixels(pixels []Camera.Pixel, pixelScale int32, renderer *sdl.Renderer) {
+	// the pixels replace what is there, the debug views turn blending on for themselves
+	if err := renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE); err != nil {
+		panic(err)
+	}
+	for _, p := range pixels {
+		wUnit := pixelScale
+		hUnit := pixelScale
 
-		x1 := startPoint.X()
-		y1 := startPoint.Y()
-		x2 := endPoint.X()
-		y2 := endPoint.Y()
+		radiance := p.Radiance()
+		c := Film.Develop(Film.Clamp{}, float32(radiance.X()), float32(radiance.Y()), float32(radiance.Z()))
+		err := renderer.SetDrawColor(c.R, c.G, c.B, 0)
+		if err != nil {
+			panic(err)
+		}
 
-		if hits {
-			drawHitLine(float32(x1), float32(y1), float32(x2), float32(y2), 30, renderer)
-		} else {
-			drawLine(float32(x1), float32(y1), float32(x2), float32(y2), 30, renderer)
+		rect := sdl.Rect{X: int32(p.X()) * wUnit, Y: int32(p.Y()) * hUnit, W: wUnit, H: hUnit}
+		err = renderer.FillRect(&rect)
+		if err != nil {
+			panic(err)
 		}
 	}
 }
 
-func drawHitLine(x1, y1, x2, y2, offset float32, renderer *sdl.Renderer) {
-	err := renderer.SetDrawColor(0, 255, 0, 50)
-	if err != nil {
-		panic(err)
-	}
-	err = renderer.DrawLineF(x1+offset, y1+offset, x2+offset, y2+offset)
-	if err != nil {
-		panic(err)
-	}
+// sdlCanvas draws the debug views into the window
+type sdlCanvas struct {
+	renderer *sdl.Renderer
 }
 
-func drawLine(x1, y1, x2, y2, offset float32, renderer *sdl.Renderer) {
-	err := renderer.SetDrawColor(200, 100, 200, 50)
+// SetColor also turns on blending, so translucent colors let the image show through
+func (c *sdlCanvas) SetColor(col color.NRGBA) {
+	err := c.renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
 	if err != nil {
 		panic(err)
 	}
-	err = renderer.DrawLineF(x1+offset, y1+offset, x2+offset, y2+offset)
+	err = c.renderer.SetDrawColor(col.R, col.G, col.B, col.A)
 	if err != nil {
 		panic(err)
 	}
 }
 
-func drawRotationLine(camera Camera.Camera, renderer *sdl.Renderer) {
-	x1, y1, x2, y2 := camera.GetRotationLine()
-	drawLine(x1, y1, x2, y2, 50, renderer)
-}
-
-func drawMiniMap(cameraPosition Vector.Vector, renderer *sdl.Renderer) {
-	err := renderer.SetDrawColor(200, 255, 100, 50)
+func (c *sdlCanvas) DrawPoint(x, y float64) {
+	err := c.renderer.DrawPointF(float32(x), float32(y))
 	if err != nil {
 		panic(err)
 	}
-	xOffset := 100.0
-	yOffset := 100.0
-
-	x1, y1 := xOffset, yOffset
-	x2, y2 := cameraPosition.X()*-30+xOffset, cameraPosition.Z()*-30+yOffset
+}
 
-	err = renderer.DrawLineF(float32(x1), float32(y1), float32(x2), float32(y2))
+func (c *sdlCanvas) DrawLine(x1, y1, x2, y2 float64) {
+	err := c.renderer.DrawLineF(float32(x1), float32(y1), float32(x2), float32(y2))
 	if err != nil {
 		panic(err)
 	}
 }
-
-func drawPixels(pixels []Camera.Pixel, w int32, h int32, renderer *sdl.Renderer) {
-	for _, p := range pixels {
-		wUnit := 600 / w
-		hUnit := 600 / h
-
-		r, g, b, _ := p.Color().RGBA()
-		err := renderer.SetDrawColor(uint8(r / 0x101), uint8(g / 0x101), uint8(b / 0x101), 0)
-		if err != nil {
-			panic(err)
-		}
-
-		rect := sdl.Rect{X: int32(p.X()) * wUnit, Y: int32(p.Y()) * hUnit, W: wUnit, H: hUnit}
-		err = renderer.FillRect(&rect)
-		if err != nil {
-			panic(err)
-		}
-	}
-}
-
@@ -0,0 +1,121 @@
+package Sampler
+
+import (
+	"fmt"
+	"math"
+)
+
+// haltonDimensions is how many dimensions get their own pair of Halton bases
+const haltonDimensions = 64
+
+// primes are the Halton bases, two per dimension
+var primes = firstPrimes(2 * haltonDimensions)
+
+// Halton uses the Halton sequence, which fills the pixel evenly however many samples
+// are taken. Every pixel and dimension shifts the points by its own random offset
+// (a Cranley-Patterson rotation) so neighbouring pixels don't repeat the same pattern.
+// Each dimension has its own bases, reusing them would only shift one dimension's
+// points against another's, so dimensions past the last bases are sampled at random.
+type Halton struct {
+	seed uint64
+}
+
+func NewHalton(seed int64) *Halton {
+	return &Halton{seed: uint64(seed)}
+}
+
+func (h *Halton) Sample(x, y, index, dimension int) (float64, float64) {
+	offset := hash(h.seed, uint64(x), uint64(y), uint64(dimension))
+	if dimension >= haltonDimensions {
+		bits := hash(offset, uint64(index))
+		return toFloat(bits), toFloat(mix(bits))
+	}
+
+	base := 2 * dimension
+
+	u := radicalInverse(primes[base], index) + toFloat(offset)
+	v := radicalInverse(primes[base+1], index) + toFloat(mix(offset))
+	return u - math.Floor(u), v - math.Floor(v)
+}
+
+func (h *Halton) String() string {
+	return fmt.Sprintf("{halton sampler, seed: %d}", h.seed)
+}
+
+// firstPrimes returns the count smallest primes
+func firstPrimes(count int) []int {
+	primes := make([]int, 0, count)
+	for candidate := 2; len(primes) < count; candidate++ {
+		prime := true
+		for _, p := range primes {
+			if p*p > candidate {
+				break
+			}
+			if candidate%p == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			primes = append(primes, candidate)
+		}
+	}
+	return primes
+}
+
+// radicalInverse mirrors the digits of index in base around the decimal point
+func radicalInverse(base, index int) float64 {
+	inverse := 0.0
+	scale := 1.0 / float64(base)
+	for index > 0 {
+		inverse += float64(index%base) * scale
+		index /= base
+		scale /= float64(base)
+	}
+	return inverse
+}
+
+// Sobol uses the first two dimensions of the Sobol sequence. Any power of two run
+// of samples puts exactly one point in each of the equal sized slices of the
+// pixel, across, down or in between. The bits are scrambled per pixel and dimension,
+// which keeps that property while breaking up the pattern.
+type Sobol struct {
+	seed uint64
+}
+
+func NewSobol(seed int64) *Sobol {
+	return &Sobol{seed: uint64(seed)}
+}
+
+func (s *Sobol) Sample(x, y, index, dimension int) (float64, float64) {
+	scramble := hash(s.seed, uint64(x), uint64(y), uint64(dimension))
+
+	u := reverseBits(uint32(index)) ^ uint32(scramble)
+	v := sobolSecondDimension(uint32(index)) ^ uint32(scramble>>32)
+	return float64(u) / (1 << 32), float64(v) / (1 << 32)
+}
+
+func (s *Sobol) String() string {
+	return fmt.Sprintf("{sobol sampler, seed: %d}", s.seed)
+}
+
+// reverseBits is the first Sobol dimension, the base 2 radical inverse as 32 bits
+func reverseBits(index uint32) uint32 {
+	index = (index << 16) | (index >> 16)
+	index = ((index & 0x00ff00ff) << 8) | ((index & 0xff00ff00) >> 8)
+	index = ((index & 0x0f0f0f0f) << 4) | ((index & 0xf0f0f0f0) >> 4)
+	index = ((index & 0x33333333) << 2) | ((index & 0xcccccccc) >> 2)
+	index = ((index & 0x55555555) << 1) | ((index & 0xaaaaaaaa) >> 1)
+	return index
+}
+
+// sobolSecondDimension applies the generator matrix of the second Sobol dimension
+func sobolSecondDimension(index uint32) uint32 {
+	result := uint32(0)
+	for direction := uint32(1 << 31); index != 0; index, direction = index>>1, direction^(direction>>1) {
+		if index&1 != 0 {
+			result ^= direction
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package Sampler
+
+import (
+	"fmt"
+	"math"
+)
+
+// Sampler places the samples taken within a pixel. Samples are a pure function of
+// the seed, pixel, sample index and dimension, so a render gives the same result
+// however its pixels are split between goroutines.
+type Sampler interface {
+	// Sample returns a point in the unit square, (0.5, 0.5) is the center of the pixel.
+	// Each dimension is an independent set of points, the camera uses 0 for the
+	// position in the pixel and 1 for the position on the lens.
+	Sample(x, y, index, dimension int) (float64, float64)
+}
+
+// Random places every sample independently at random
+type Random struct {
+	seed uint64
+}
+
+func NewRandom(seed int64) *Random {
+	return &Random{seed: uint64(seed)}
+}
+
+func (r *Random) Sample(x, y, index, dimension int) (float64, float64) {
+	bits := hash(r.seed, uint64(x), uint64(y), uint64(index), uint64(dimension))
+	return toFloat(bits), toFloat(mix(bits))
+}
+
+func (r *Random) String() string {
+	return fmt.Sprintf("{random sampler, seed: %d}", r.seed)
+}
+
+// Stratified splits the pixel into a grid with a cell per sample and jitters each
+// sample within its cell. The grid is the factorisation of the sample count closest
+// to square, so every cell is used and the samples stay centered on the pixel. Indices
+// past the sample count wrap around to the first cells.
+type Stratified struct {
+	samples int
+	columns int
+	rows    int
+	seed    uint64
+}
+
+func NewStratified(samplesPerPixel int, seed int64) *Stratified {
+	if samplesPerPixel < 1 {
+		samplesPerPixel = 1
+	}
+	rows := int(math.Sqrt(float64(samplesPerPixel)))
+	for samplesPerPixel%rows != 0 {
+		rows--
+	}
+	columns := samplesPerPixel / rows
+	return &Stratified{
+		samples: samplesPerPixel,
+		columns: columns,
+		rows:    rows,
+		seed:    uint64(seed),
+	}
+}
+
+func (s *Stratified) Sample(x, y, index, dimension int) (float64, float64) {
+	bits := hash(s.seed, uint64(x), uint64(y), uint64(index), uint64(dimension))
+
+	// every dimension visits the cells in its own order so they don't correlate
+	shift := hash(s.seed, uint64(x), uint64(y), uint64(dimension)) % uint64(s.samples)
+	cell := (index + int(shift)) % s.samples
+
+	column, row := cell%s.columns, cell/s.columns
+	u := (float64(column) + toFloat(bits)) / float64(s.columns)
+	v := (float64(row) + toFloat(mix(bits))) / float64(s.rows)
+	return u, v
+}
+
+func (s *Stratified) String() string {
+	return fmt.Sprintf("{stratified sampler, samples: %d, seed: %d}", s.samples, s.seed)
+}
+
+// hash mixes values into 64 evenly spread bits
+func hash(values ...uint64) uint64 {
+	h := uint64(0x9e3779b97f4a7c15)
+	for _, value := range values {
+		h = mix(h ^ value)
+	}
+	return h
+}
+
+// mix is the splitmix64 finalizer
+func mix(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// toFloat turns the top 53 bits into a float in [0, 1)
+func toFloat(bits uint64) float64 {
+	return float64(bits>>11) / (1 << 53)
+}
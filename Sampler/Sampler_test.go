@@ -0,0 +1,161 @@
+package Sampler
+
+import (
+	"math"
+	"testing"
+)
+
+func allSamplers(seed int64) []Sampler {
+	return []Sampler{NewRandom(seed), NewStratified(16, seed), NewHalton(seed), NewSobol(seed)}
+}
+
+func TestSamplesInUnitSquare(t *testing.T) {
+	for i, sampler := range allSamplers(7) {
+		for pixel := 0; pixel < 50; pixel++ {
+			for index := 0; index < 64; index++ {
+				for dimension := 0; dimension < 3; dimension++ {
+					u, v := sampler.Sample(pixel, pixel*3, index, dimension)
+					if u < 0 || u >= 1 || v < 0 || v >= 1 {
+						t.Fatalf("Test %d: Expected a sample in the unit square, got %v, %v", i+1, u, v)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSamplesAreCentered(t *testing.T) {
+	tests := []struct {
+		sampler Sampler
+		samples int
+	}{
+		{sampler: NewRandom(1), samples: 16},
+		{sampler: NewStratified(16, 1), samples: 16},
+		{sampler: NewStratified(15, 1), samples: 15},
+		{sampler: NewStratified(5, 1), samples: 5},
+		{sampler: NewHalton(1), samples: 16},
+		{sampler: NewSobol(1), samples: 16},
+	}
+	for i, tt := range tests {
+		sumU, sumV, count := 0.0, 0.0, 0
+		for x := 0; x < 32; x++ {
+			for y := 0; y < 32; y++ {
+				for index := 0; index < tt.samples; index++ {
+					u, v := tt.sampler.Sample(x, y, index, 0)
+					sumU += u
+					sumV += v
+					count++
+				}
+			}
+		}
+		if meanU, meanV := sumU/float64(count), sumV/float64(count); math.Abs(meanU-0.5) > 0.01 || math.Abs(meanV-0.5) > 0.01 {
+			t.Errorf("Test %d: Expected samples centered on the pixel, mean was %v, %v", i+1, meanU, meanV)
+		}
+	}
+}
+
+func TestSamplesDependOnlyOnSeed(t *testing.T) {
+	first, again, other := allSamplers(42), allSamplers(42), allSamplers(43)
+	for i := range first {
+		differs := false
+		for index := 0; index < 16; index++ {
+			u1, v1 := first[i].Sample(3, 5, index, 1)
+			u2, v2 := again[i].Sample(3, 5, index, 1)
+			u3, v3 := other[i].Sample(3, 5, index, 1)
+			if u1 != u2 || v1 != v2 {
+				t.Errorf("Test %d: Expected the same seed to give the same samples", i+1)
+			}
+			if u1 != u3 || v1 != v3 {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Errorf("Test %d: Expected another seed to give other samples", i+1)
+		}
+	}
+}
+
+func TestStratifiedCoversEveryCell(t *testing.T) {
+	tests := []struct {
+		samples       int
+		columns, rows int
+	}{
+		{samples: 16, columns: 4, rows: 4},
+		{samples: 6, columns: 3, rows: 2},
+		{samples: 15, columns: 5, rows: 3},
+		{samples: 5, columns: 5, rows: 1},
+		{samples: 1, columns: 1, rows: 1},
+	}
+	for i, tt := range tests {
+		sampler := NewStratified(tt.samples, 3)
+		for dimension := 0; dimension < 2; dimension++ {
+			seen := map[int]bool{}
+			for index := 0; index < tt.samples; index++ {
+				u, v := sampler.Sample(9, 4, index, dimension)
+				seen[int(v*float64(tt.rows))*tt.columns+int(u*float64(tt.columns))] = true
+			}
+			if len(seen) != tt.samples {
+				t.Errorf("Test %d: Expected %d cells to be covered, got %d", i+1, tt.samples, len(seen))
+			}
+		}
+	}
+}
+
+func TestRadicalInverse(t *testing.T) {
+	tests := []struct {
+		base, index int
+		expected    float64
+	}{
+		{base: 2, index: 0, expected: 0},
+		{base: 2, index: 1, expected: 0.5},
+		{base: 2, index: 2, expected: 0.25},
+		{base: 2, index: 3, expected: 0.75},
+		{base: 2, index: 6, expected: 0.375},
+		{base: 3, index: 1, expected: 1.0 / 3},
+		{base: 3, index: 5, expected: 2.0/3 + 1.0/9},
+	}
+	for i, tt := range tests {
+		if got := radicalInverse(tt.base, tt.index); math.Abs(got-tt.expected) > 1e-12 {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, tt.expected, got)
+		}
+	}
+}
+
+func TestHaltonDimensionsAreIndependent(t *testing.T) {
+	// a dimension sharing another's bases would only be shifted against it, so the
+	// difference between their samples would be the same for every index
+	sampler := NewHalton(5)
+	for dimension := 0; dimension < haltonDimensions+2; dimension++ {
+		for _, other := range []int{dimension + 1, dimension + 8} {
+			firstU, _ := sampler.Sample(4, 7, 0, dimension)
+			firstOtherU, _ := sampler.Sample(4, 7, 0, other)
+			shifted := true
+			for index := 1; index < 16; index++ {
+				u, _ := sampler.Sample(4, 7, index, dimension)
+				otherU, _ := sampler.Sample(4, 7, index, other)
+				difference := math.Mod(otherU-u-(firstOtherU-firstU)+2, 1)
+				if math.Min(difference, 1-difference) > 1e-9 {
+					shifted = false
+				}
+			}
+			if shifted {
+				t.Errorf("Expected dimensions %d and %d to be independent", dimension, other)
+			}
+		}
+	}
+}
+
+func TestSobolStratifiesPowersOfTwo(t *testing.T) {
+	sampler := NewSobol(11)
+	for _, columns := range []int{1, 2, 4, 8, 16} {
+		rows := 16 / columns
+		seen := map[int]bool{}
+		for index := 0; index < 16; index++ {
+			u, v := sampler.Sample(2, 8, index, 0)
+			seen[int(v*float64(rows))*columns+int(u*float64(columns))] = true
+		}
+		if len(seen) != 16 {
+			t.Errorf("Expected one of 16 samples in each %dx%d cell, %d cells were covered", columns, rows, len(seen))
+		}
+	}
+}
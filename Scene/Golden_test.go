@@ -0,0 +1,75 @@
+package Scene
+
+import (
+	"flag"
+	"goRay/Film"
+	"goRay/Utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden images in testdata/golden")
+
+// goldenTolerance is the RMSE a render may differ from its golden image by, enough
+// for rounding differences between platforms but not for a visible change
+const goldenTolerance = 0.01
+
+// TestGolden renders every scene in testdata and compares it to the image of the same
+// name in testdata/golden. Failed renders and their differences are written to the
+// temp directory. After an intended change, regenerate the images with
+//
+//	go test ./Scene -run TestGolden -update
+func TestGolden(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("Expected golden scenes in testdata")
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		t.Run(name, func(t *testing.T) {
+			camera, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			img := camera.Render().Image(Film.Clamp{})
+
+			goldenPath := filepath.Join("testdata", "golden", name+".png")
+			if *update {
+				if err := Utils.WritePNG(goldenPath, img); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			golden, err := Utils.ReadPNG(goldenPath)
+			if err != nil {
+				t.Fatalf("%v, regenerate the golden images with -update", err)
+			}
+			rmse, err := Utils.RMSE(golden, img)
+			if err == nil && rmse <= goldenTolerance {
+				return
+			}
+
+			failures := filepath.Join(os.TempDir(), "goRay-golden")
+			actualPath := filepath.Join(failures, name+"-actual.png")
+			diffPath := filepath.Join(failures, name+"-diff.png")
+			if err := Utils.WritePNG(actualPath, img); err != nil {
+				t.Error(err)
+			}
+			if err := Utils.WritePNG(diffPath, Utils.DiffImage(golden, img)); err != nil {
+				t.Error(err)
+			}
+			if err != nil {
+				t.Fatalf("%v, the render is in %s", err, actualPath)
+			}
+			t.Errorf("Expected an RMSE of at most %v against %s, got %.4f. The render is in %s and the difference in %s",
+				goldenTolerance, goldenPath, rmse, actualPath, diffPath)
+		})
+	}
+}
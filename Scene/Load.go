@@ -0,0 +1,617 @@
+package Scene
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goRay/Camera"
+	"goRay/Texture"
+	"goRay/Vector"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Error describes a problem with a scene file. Line is set for errors found while
+// decoding, Path is the field path (e.g. "objects[2].radius") when it is known.
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Path   string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	var location []string
+	if e.File != "" {
+		location = append(location, e.File)
+	}
+	if e.Line > 0 {
+		location = append(location, fmt.Sprintf("%d:%d", e.Line, e.Column))
+	}
+	if e.Path != "" {
+		location = append(location, e.Path)
+	}
+	if len(location) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", strings.Join(location, ": "), e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Load reads a scene file and builds a camera from it
+func Load(path string) (*Camera.Camera, error) {
+	description, err := LoadDescription(path)
+	if err != nil {
+		return nil, err
+	}
+	return description.Build()
+}
+
+// LoadDescription reads and validates a scene file without building it
+func LoadDescription(path string) (*Description, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := Parse(data)
+	if err != nil {
+		return nil, withFile(err, path)
+	}
+	description.file = path
+	description.dir = filepath.Dir(path)
+	return description, nil
+}
+
+// Parse decodes and validates a scene description
+func Parse(data []byte) (*Description, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var description Description
+	if err := decoder.Decode(&description); err != nil {
+		return nil, decodeError(data, err)
+	}
+	if decoder.More() {
+		line, column := position(data, decoder.InputOffset())
+		return nil, &Error{Line: line, Column: column, Err: errors.New("unexpected data after the scene")}
+	}
+
+	if err := description.validate(); err != nil {
+		if offset, ok := findPath(locate(data), err.Path); ok {
+			err.Line, err.Column = position(data, offset)
+		}
+		return nil, err
+	}
+	return &description, nil
+}
+
+func decodeError(data []byte, err error) error {
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxError):
+		line, column := position(data, syntaxError.Offset)
+		return &Error{Line: line, Column: column, Err: errors.New(syntaxError.Error())}
+	case errors.As(err, &typeError):
+		line, column := position(data, typeError.Offset)
+		return &Error{
+			Line:   line,
+			Column: column,
+			Path:   fieldPath(typeError.Field),
+			Err:    fmt.Errorf("expected %s, got %s", typeError.Type, typeError.Value),
+		}
+	}
+
+	message := strings.TrimPrefix(err.Error(), "json: ")
+	sceneError := &Error{Err: errors.New(message)}
+	if field, ok := strings.CutPrefix(message, "unknown field "); ok {
+		field, _ = strconv.Unquote(field)
+		if location, ok := findField(locate(data), field); ok {
+			sceneError.Path = location.path
+			sceneError.Line, sceneError.Column = position(data, location.offset)
+		}
+	}
+	return sceneError
+}
+
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// fieldPath rewrites encoding/json's "objects.0.radius" as "objects[0].radius"
+func fieldPath(field string) string {
+	var path strings.Builder
+	for i, segment := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(segment); err == nil {
+			fmt.Fprintf(&path, "[%s]", segment)
+			continue
+		}
+		if i > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(segment)
+	}
+	return path.String()
+}
+
+type location struct {
+	path   string
+	offset int64
+}
+
+// locate walks the document and records where every field and array element starts
+func locate(data []byte) []location {
+	type container struct {
+		path   string
+		array  bool
+		index  int
+		key    string
+		hasKey bool
+	}
+
+	var locations []location
+	var stack []*container
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		start := skipSeparators(data, decoder.InputOffset())
+		token, err := decoder.Token()
+		if err != nil {
+			return locations
+		}
+
+		delim, isDelim := token.(json.Delim)
+		if isDelim && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		path := ""
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			switch {
+			case top.array:
+				path = fmt.Sprintf("%s[%d]", top.path, top.index)
+				top.index++
+				locations = append(locations, location{path: path, offset: start})
+			case !top.hasKey:
+				top.key, top.hasKey = token.(string), true
+				locations = append(locations, location{path: joinPath(top.path, top.key), offset: start})
+				continue
+			default:
+				path = joinPath(top.path, top.key)
+				top.hasKey = false
+			}
+		}
+
+		if isDelim {
+			stack = append(stack, &container{path: path, array: delim == '['})
+		}
+	}
+}
+
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func findPath(locations []location, path string) (int64, bool) {
+	for _, l := range locations {
+		if l.path == path {
+			return l.offset, true
+		}
+	}
+	return 0, false
+}
+
+func findField(locations []location, field string) (location, bool) {
+	for _, l := range locations {
+		if l.path == field || strings.HasSuffix(l.path, "."+field) {
+			return l, true
+		}
+	}
+	return location{}, false
+}
+
+func withFile(err error, file string) error {
+	var sceneError *Error
+	if errors.As(err, &sceneError) {
+		sceneError.File = file
+		return sceneError
+	}
+	return fmt.Errorf("%s: %w", file, err)
+}
+
+// Validate checks a description against the rules Parse applies, for descriptions
+// changed after they were loaded. Errors carry the field path of the problem.
+func (d *Description) Validate() error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *Description) validate() *Error {
+	if d.Version == 0 {
+		return &Error{Path: "version", Err: errors.New("missing, expected a scene format version")}
+	}
+	if d.Version < 0 || d.Version > CurrentVersion {
+		return &Error{Path: "version", Err: fmt.Errorf("unsupported version %d, this build reads up to %d", d.Version, CurrentVersion)}
+	}
+
+	if d.Camera.Width <= 0 {
+		return &Error{Path: "camera.width", Err: errors.New("must be positive")}
+	}
+	if d.Camera.Height <= 0 {
+		return &Error{Path: "camera.height", Err: errors.New("must be positive")}
+	}
+	if d.Camera.AntiAliasing < 0 {
+		return &Error{Path: "camera.antiAliasing", Err: errors.New("must not be negative")}
+	}
+	if d.Camera.MaxDepth < 0 {
+		return &Error{Path: "camera.maxDepth", Err: errors.New("must not be negative")}
+	}
+	if d.Camera.ShadowSamples < 0 {
+		return &Error{Path: "camera.shadowSamples", Err: errors.New("must not be negative")}
+	}
+	if err := validateFieldOfView("camera.fov", d.Camera.FieldOfView); err != nil {
+		return err
+	}
+	if err := validateFieldOfView("camera.verticalFov", d.Camera.VerticalFieldOfView); err != nil {
+		return err
+	}
+	if d.Camera.FieldOfView != 0 && d.Camera.VerticalFieldOfView != 0 {
+		return &Error{Path: "camera.verticalFov", Err: errors.New("can't be combined with fov")}
+	}
+	if d.Camera.Aperture < 0 {
+		return &Error{Path: "camera.aperture", Err: errors.New("must not be negative")}
+	}
+	if d.Camera.Aperture > 0 && d.Camera.FocalDistance <= 0 {
+		return &Error{Path: "camera.focalDistance", Err: errors.New("must be positive when aperture is set")}
+	}
+	if d.Camera.Projection != nil {
+		if err := d.Camera.Projection.validate(); err != nil {
+			err.Path = "camera.projection." + err.Path
+			return err
+		}
+		if d.Camera.Projection.Type != "perspective" && (d.Camera.FieldOfView != 0 || d.Camera.VerticalFieldOfView != 0) {
+			return &Error{Path: "camera.fov", Err: errors.New("only used by the perspective projection")}
+		}
+	}
+	if sampler := d.Camera.Sampler; sampler != nil && !slices.Contains(SamplerTypes, sampler.Type) {
+		return &Error{Path: "camera.sampler.type", Err: fmt.Errorf("unknown sampler type %q", sampler.Type)}
+	}
+	if integrator := d.Camera.Integrator; integrator != "" && !slices.Contains(Camera.IntegratorNames, integrator) {
+		return &Error{Path: "camera.integrator", Err: fmt.Errorf("unknown integrator %q", integrator)}
+	}
+	if err := d.Camera.validateOrientation(); err != nil {
+		err.Path = "camera." + err.Path
+		return err
+	}
+
+	for i, object := range d.Objects {
+		if err := object.validate(); err != nil {
+			err.Path = fmt.Sprintf("objects[%d].%s", i, err.Path)
+			return err
+		}
+	}
+
+	for i, light := range d.Lights {
+		if err := light.validate(); err != nil {
+			err.Path = fmt.Sprintf("lights[%d].%s", i, err.Path)
+			return err
+		}
+	}
+
+	if environment := d.Environment; environment != nil {
+		if environment.Path == "" {
+			return &Error{Path: "environment.path", Err: errors.New("missing")}
+		}
+		if environment.Intensity < 0 {
+			return &Error{Path: "environment.intensity", Err: errors.New("must not be negative")}
+		}
+	}
+	return nil
+}
+
+func (c CameraDescription) validateOrientation() *Error {
+	if c.LookAt == nil {
+		if c.Up != nil {
+			return &Error{Path: "up", Err: errors.New("only used together with lookAt")}
+		}
+		return nil
+	}
+	if c.Rotation != (RotationDescription{}) {
+		return &Error{Path: "rotation", Err: errors.New("can't be combined with lookAt")}
+	}
+	if err := validateVector("lookAt", c.LookAt); err != nil {
+		return err
+	}
+	if c.LookAt.Vector() == c.Origin.Vector() {
+		return &Error{Path: "lookAt", Err: errors.New("must differ from the origin")}
+	}
+	if c.Up != nil {
+		if err := validateVector("up", c.Up); err != nil {
+			return err
+		}
+		if c.Up.Vector() == (Vector.Vector{}) {
+			return &Error{Path: "up", Err: errors.New("must not be zero")}
+		}
+	}
+	return nil
+}
+
+func (o ObjectDescription) validate() *Error {
+	if err := validateVector("color", o.Color); err != nil {
+		return err
+	}
+	if o.Material != nil {
+		if err := o.Material.validate(); err != nil {
+			err.Path = "material." + err.Path
+			return err
+		}
+	}
+
+	switch o.Type {
+	case "sphere":
+		if err := validateVector("center", o.Center); err != nil {
+			return err
+		}
+		if o.Radius <= 0 {
+			return &Error{Path: "radius", Err: errors.New("must be positive")}
+		}
+	case "plane":
+		if err := validateVector("point", o.Point); err != nil {
+			return err
+		}
+		if err := validateVector("normal", o.Normal); err != nil {
+			return err
+		}
+		if o.Normal.Vector() == (Vector.Vector{}) {
+			return &Error{Path: "normal", Err: errors.New("must not be zero")}
+		}
+	case "box":
+		if err := validateVector("min", o.Min); err != nil {
+			return err
+		}
+		if err := validateVector("max", o.Max); err != nil {
+			return err
+		}
+	case "mesh":
+		if o.Path == "" {
+			return &Error{Path: "path", Err: errors.New("missing")}
+		}
+	case "":
+		return &Error{Path: "type", Err: errors.New("missing")}
+	default:
+		return &Error{Path: "type", Err: fmt.Errorf("unknown object type %q", o.Type)}
+	}
+	return nil
+}
+
+func (m MaterialDescription) validate() *Error {
+	if m.Specular != nil {
+		if err := validateVector("specular", m.Specular); err != nil {
+			return err
+		}
+	}
+	if m.Ambient != nil {
+		if err := validateVector("ambient", m.Ambient); err != nil {
+			return err
+		}
+	}
+	if m.Shininess < 0 {
+		return &Error{Path: "shininess", Err: errors.New("must not be negative")}
+	}
+	if m.Reflectivity < 0 || m.Reflectivity > 1 {
+		return &Error{Path: "reflectivity", Err: errors.New("must be between 0 and 1")}
+	}
+	if m.IOR != 0 && m.IOR < 1 {
+		return &Error{Path: "ior", Err: errors.New("must be at least 1")}
+	}
+	if m.Emission != nil {
+		if err := validateVector("emission", m.Emission); err != nil {
+			return err
+		}
+		for _, channel := range m.Emission {
+			if channel < 0 {
+				return &Error{Path: "emission", Err: errors.New("must not be negative")}
+			}
+		}
+		if m.IOR != 0 {
+			return &Error{Path: "emission", Err: errors.New("can't be combined with ior")}
+		}
+	}
+	if m.Texture != nil {
+		if m.IOR != 0 || m.Emission != nil {
+			return &Error{Path: "texture", Err: errors.New("only used by Blinn-Phong materials")}
+		}
+		if err := m.Texture.validate(); err != nil {
+			err.Path = "texture." + err.Path
+			return err
+		}
+	}
+	if m.NormalMap != nil {
+		if m.IOR != 0 || m.Emission != nil {
+			return &Error{Path: "normalMap", Err: errors.New("only used by Blinn-Phong materials")}
+		}
+		if err := validateImage(m.NormalMap.Path, m.NormalMap.Wrap); err != nil {
+			err.Path = "normalMap." + err.Path
+			return err
+		}
+		if m.NormalMap.Strength != nil && *m.NormalMap.Strength < 0 {
+			return &Error{Path: "normalMap.strength", Err: errors.New("must not be negative")}
+		}
+	}
+	if m.Bump != nil {
+		if m.IOR != 0 || m.Emission != nil {
+			return &Error{Path: "bump", Err: errors.New("only used by Blinn-Phong materials")}
+		}
+		if m.NormalMap != nil {
+			return &Error{Path: "bump", Err: errors.New("can't be combined with normalMap")}
+		}
+		if err := validateImage(m.Bump.Path, m.Bump.Wrap); err != nil {
+			err.Path = "bump." + err.Path
+			return err
+		}
+		if m.Bump.Scale <= 0 {
+			return &Error{Path: "bump.scale", Err: errors.New("must be positive")}
+		}
+	}
+	return nil
+}
+
+// validateImage checks the fields every image in a scene has
+func validateImage(path, wrap string) *Error {
+	if path == "" {
+		return &Error{Path: "path", Err: errors.New("missing")}
+	}
+	if wrap != "" && !slices.Contains(Texture.WrapModeNames, wrap) {
+		return &Error{Path: "wrap", Err: fmt.Errorf("unknown wrap mode %q", wrap)}
+	}
+	return nil
+}
+
+func (t TextureDescription) validate() *Error {
+	switch t.Type {
+	case "checker":
+		if err := validateVector("even", t.Even); err != nil {
+			return err
+		}
+		if err := validateVector("odd", t.Odd); err != nil {
+			return err
+		}
+		if t.Frequency < 0 {
+			return &Error{Path: "frequency", Err: errors.New("must not be negative")}
+		}
+	case "noise":
+		if err := validateVector("low", t.Low); err != nil {
+			return err
+		}
+		if err := validateVector("high", t.High); err != nil {
+			return err
+		}
+		if t.Scale < 0 {
+			return &Error{Path: "scale", Err: errors.New("must not be negative")}
+		}
+		if t.Octaves < 0 {
+			return &Error{Path: "octaves", Err: errors.New("must not be negative")}
+		}
+	case "image":
+		return validateImage(t.Path, t.Wrap)
+	case "":
+		return &Error{Path: "type", Err: errors.New("missing")}
+	default:
+		return &Error{Path: "type", Err: fmt.Errorf("unknown texture type %q", t.Type)}
+	}
+	return nil
+}
+
+func (l LightDescription) validate() *Error {
+	if err := validateVector("color", l.Color); err != nil {
+		return err
+	}
+	if l.Intensity < 0 {
+		return &Error{Path: "intensity", Err: errors.New("must not be negative")}
+	}
+
+	switch l.Type {
+	case "point":
+		return validateVector("position", l.Position)
+	case "directional":
+		if err := validateVector("direction", l.Direction); err != nil {
+			return err
+		}
+		if l.Direction.Vector() == (Vector.Vector{}) {
+			return &Error{Path: "direction", Err: errors.New("must not be zero")}
+		}
+	case "sphere":
+		if l.Radius < 0 {
+			return &Error{Path: "radius", Err: errors.New("must not be negative")}
+		}
+		return validateVector("position", l.Position)
+	case "rect":
+		if err := validateVector("position", l.Position); err != nil {
+			return err
+		}
+		if err := validateVector("edgeU", l.EdgeU); err != nil {
+			return err
+		}
+		if err := validateVector("edgeV", l.EdgeV); err != nil {
+			return err
+		}
+		if l.EdgeU.Vector().Cross(l.EdgeV.Vector()) == (Vector.Vector{}) {
+			return &Error{Path: "edgeV", Err: errors.New("must not be zero or parallel to edgeU")}
+		}
+	case "":
+		return &Error{Path: "type", Err: errors.New("missing")}
+	default:
+		return &Error{Path: "type", Err: fmt.Errorf("unknown light type %q", l.Type)}
+	}
+	return nil
+}
+
+func (p ProjectionDescription) validate() *Error {
+	switch p.Type {
+	case "perspective", "equirectangular":
+	case "orthographic":
+		if p.Height < 0 {
+			return &Error{Path: "height", Err: errors.New("must not be negative")}
+		}
+	case "fisheye":
+		if p.FieldOfView < 0 || p.FieldOfView > 360 {
+			return &Error{Path: "fov", Err: errors.New("must be between 0 and 360 degrees")}
+		}
+	default:
+		return &Error{Path: "type", Err: fmt.Errorf("unknown projection type %q", p.Type)}
+	}
+
+	if p.Height != 0 && p.Type != "orthographic" {
+		return &Error{Path: "height", Err: errors.New("only used by the orthographic projection")}
+	}
+	if p.FieldOfView != 0 && p.Type != "fisheye" {
+		return &Error{Path: "fov", Err: errors.New("only used by the fisheye projection")}
+	}
+	return nil
+}
+
+func validateFieldOfView(path string, degrees float64) *Error {
+	if degrees < 0 || degrees >= 180 {
+		return &Error{Path: path, Err: errors.New("must be between 0 and 180 degrees")}
+	}
+	return nil
+}
+
+func validateVector(path string, v Vec3) *Error {
+	if v == nil {
+		return &Error{Path: path, Err: errors.New("missing")}
+	}
+	if len(v) != 3 {
+		return &Error{Path: path, Err: fmt.Errorf("expected 3 numbers, got %d", len(v))}
+	}
+	return nil
+}
@@ -0,0 +1,494 @@
+package Scene
+
+import (
+	_ "embed"
+	"fmt"
+	"goRay/Camera"
+	"goRay/Environment"
+	"goRay/Light"
+	"goRay/Material"
+	"goRay/Object"
+	"goRay/Sampler"
+	"goRay/Texture"
+	"goRay/Vector"
+	"math"
+	"path/filepath"
+)
+
+// CurrentVersion is the newest scene format version this loader understands
+const CurrentVersion = 1
+
+//go:embed default.json
+var defaultScene []byte
+
+// Description is the on-disk form of a scene. Everything in it is plain data so
+// that scenes can be written and tweaked without recompiling.
+type Description struct {
+	Version int                 `json:"version"`
+	Camera  CameraDescription   `json:"camera"`
+	Objects []ObjectDescription `json:"objects"`
+	Lights  []LightDescription  `json:"lights"`
+	// Environment replaces the default sky, lighting the scene as well
+	Environment *EnvironmentDescription `json:"environment"`
+
+	// file is the scene file the description was loaded from, errors building it name it
+	file string
+	// dir is the directory the scene was loaded from, relative asset paths resolve against it
+	dir string
+}
+
+type CameraDescription struct {
+	Width        int                 `json:"width"`
+	Height       int                 `json:"height"`
+	Origin       Vec3                `json:"origin"`
+	Rotation     RotationDescription `json:"rotation"`
+	AntiAliasing int                 `json:"antiAliasing"`
+	// MaxDepth limits mirror bounces, the camera default is used when it is 0
+	MaxDepth int `json:"maxDepth"`
+	// ShadowSamples is how many shadow rays go towards each area light per hit, the
+	// camera default is used when it is 0
+	ShadowSamples int `json:"shadowSamples"`
+	// LookAt turns the camera towards a point instead of using rotation, Up defaults to -y
+	LookAt Vec3 `json:"lookAt"`
+	Up     Vec3 `json:"up"`
+	// FieldOfView is horizontal and VerticalFieldOfView vertical, in degrees. Only one may be
+	// set, without either the original projection zoomed by the image height is used.
+	FieldOfView         float64 `json:"fov"`
+	VerticalFieldOfView float64 `json:"verticalFov"`
+	// Aperture is the lens radius, objects FocalDistance in front of the camera stay sharp
+	Aperture      float64 `json:"aperture"`
+	FocalDistance float64 `json:"focalDistance"`
+	// Projection defaults to perspective
+	Projection *ProjectionDescription `json:"projection"`
+	// Sampler defaults to random samples seeded with 0
+	Sampler *SamplerDescription `json:"sampler"`
+	// Integrator is one of Camera.IntegratorNames, whitted when empty
+	Integrator string `json:"integrator"`
+}
+
+// SamplerTypes lists the anti-aliasing samplers a scene can pick
+var SamplerTypes = []string{"random", "stratified", "halton", "sobol"}
+
+// SamplerDescription picks where anti-aliasing and lens samples fall, the same seed
+// always renders the same image
+type SamplerDescription struct {
+	Type string `json:"type"`
+	Seed int64  `json:"seed"`
+}
+
+// ProjectionTypes lists the projections a scene can pick
+var ProjectionTypes = []string{"perspective", "orthographic", "fisheye", "equirectangular"}
+
+// defaultOrthographicHeight is about what the original perspective shows 50 units away
+const defaultOrthographicHeight = 50
+
+// defaultFisheyeFieldOfView is in degrees
+const defaultFisheyeFieldOfView = 180
+
+// ProjectionDescription picks how pixels map to rays:
+//
+//	perspective:     uses the camera fov
+//	orthographic:    height of the view in world units, 50 when not set
+//	fisheye:         fov in degrees across the image circle, 180 when not set
+//	equirectangular: a full 360 by 180 degree panorama
+type ProjectionDescription struct {
+	Type        string  `json:"type"`
+	FieldOfView float64 `json:"fov"`
+	Height      float64 `json:"height"`
+}
+
+// RotationDescription holds camera angles in degrees, positive pitch looks up
+type RotationDescription struct {
+	Yaw   float64 `json:"yaw"`
+	Pitch float64 `json:"pitch"`
+	Roll  float64 `json:"roll"`
+}
+
+// ObjectDescription holds the fields of every object type, which of them are
+// required depends on Type:
+//
+//	sphere: center, radius
+//	plane:  point, normal
+//	box:    min, max
+//	mesh:   path to a Wavefront .obj file, relative to the scene file
+//
+// Color is the diffuse color, the optional material adds highlights and ambient light.
+type ObjectDescription struct {
+	Type     string               `json:"type"`
+	Color    Vec3                 `json:"color"`
+	Material *MaterialDescription `json:"material"`
+	Center   Vec3                 `json:"center"`
+	Radius   int                  `json:"radius"`
+	Point    Vec3                 `json:"point"`
+	Normal   Vec3                 `json:"normal"`
+	Min      Vec3                 `json:"min"`
+	Max      Vec3                 `json:"max"`
+	Path     string               `json:"path"`
+}
+
+// MaterialDescription is a Blinn-Phong material, glass like when ior is set or a
+// glowing surface when emission is set. Transparent materials use the object color
+// as their tint and glowing ones only use the emission, both ignore the other fields.
+type MaterialDescription struct {
+	Specular     Vec3    `json:"specular"`
+	Shininess    float64 `json:"shininess"`
+	Ambient      Vec3    `json:"ambient"`
+	Reflectivity float64 `json:"reflectivity"`
+	IOR          float64 `json:"ior"`
+	Emission     Vec3    `json:"emission"`
+	// Texture replaces the object color of a Blinn-Phong material
+	Texture *TextureDescription `json:"texture"`
+	// NormalMap and Bump add detail to the shading of a Blinn-Phong material, only
+	// one of them may be set
+	NormalMap *NormalMapDescription `json:"normalMap"`
+	Bump      *BumpDescription      `json:"bump"`
+}
+
+// TextureTypes lists the textures a material can carry
+var TextureTypes = []string{"checker", "noise", "image"}
+
+// defaultNoiseOctaves adds detail down to a sixteenth of the noise scale
+const defaultNoiseOctaves = 4
+
+// TextureDescription holds the fields of every texture type:
+//
+//	checker: even and odd colors, frequency squares per unit of texture coordinates
+//	noise:   low and high colors, scale features per unit of space, octaves and seed
+//	image:   path to a PNG or JPEG image, relative to the scene file, and wrap
+//
+// Frequency and scale are 1 and octaves is 4 when they are 0, wrap is "repeat" when empty.
+type TextureDescription struct {
+	Type      string  `json:"type"`
+	Even      Vec3    `json:"even"`
+	Odd       Vec3    `json:"odd"`
+	Frequency float64 `json:"frequency"`
+	Low       Vec3    `json:"low"`
+	High      Vec3    `json:"high"`
+	Scale     float64 `json:"scale"`
+	Octaves   int     `json:"octaves"`
+	Seed      int64   `json:"seed"`
+	Path      string  `json:"path"`
+	Wrap      string  `json:"wrap"`
+}
+
+// LightDescription holds the fields of every light type:
+//
+//	point:       position
+//	directional: direction the light travels in
+//	sphere:      position of the center, radius
+//	rect:        position of a corner, edgeU and edgeV, shining towards edgeU × edgeV
+type LightDescription struct {
+	Type      string  `json:"type"`
+	Color     Vec3    `json:"color"`
+	Intensity float64 `json:"intensity"`
+	Position  Vec3    `json:"position"`
+	Direction Vec3    `json:"direction"`
+	Radius    float64 `json:"radius"`
+	EdgeU     Vec3    `json:"edgeU"`
+	EdgeV     Vec3    `json:"edgeV"`
+}
+
+// EnvironmentDescription is an equirectangular .hdr image surrounding the scene, a
+// relative path is resolved against the scene file's directory
+type EnvironmentDescription struct {
+	Path string `json:"path"`
+	// Rotation turns the image around the vertical axis in degrees
+	Rotation float64 `json:"rotation"`
+	// Intensity scales the image's light, 1 when it is 0
+	Intensity float64 `json:"intensity"`
+}
+
+// NormalMapDescription is a PNG or JPEG image of tangent space normals, relative to
+// the scene file. Strength scales how far the normals tilt, 1 when it is left out and
+// 0 keeping the surface flat.
+type NormalMapDescription struct {
+	Path     string   `json:"path"`
+	Wrap     string   `json:"wrap"`
+	Strength *float64 `json:"strength"`
+}
+
+// BumpDescription is a PNG or JPEG image of heights, relative to the scene file.
+// Scale is how high white stands over black in the units of the scene, so the same
+// bumps look steeper on a smaller object.
+type BumpDescription struct {
+	Path  string  `json:"path"`
+	Wrap  string  `json:"wrap"`
+	Scale float64 `json:"scale"`
+}
+
+// Vec3 is written as a JSON array, validation makes sure it holds exactly three numbers
+type Vec3 []float64
+
+func (v Vec3) Vector() Vector.Vector {
+	if len(v) != 3 {
+		return Vector.Vector{}
+	}
+	return *Vector.New(v[0], v[1], v[2])
+}
+
+// DefaultDescription returns the built in demo scene
+func DefaultDescription() *Description {
+	description, err := Parse(defaultScene)
+	if err != nil {
+		panic(fmt.Sprintf("built in scene is invalid: %v", err))
+	}
+	return description
+}
+
+// Default builds the built in demo scene
+func Default() *Camera.Camera {
+	camera, err := DefaultDescription().Build()
+	if err != nil {
+		panic(fmt.Sprintf("built in scene is invalid: %v", err))
+	}
+	return camera
+}
+
+// Build creates a camera holding every object in the scene. Errors of a description
+// loaded from a file start with the file's path.
+func (d *Description) Build() (*Camera.Camera, error) {
+	camera, err := d.build()
+	if err != nil && d.file != "" {
+		return nil, withFile(err, d.file)
+	}
+	return camera, err
+}
+
+func (d *Description) build() (*Camera.Camera, error) {
+	origin := d.Camera.Origin.Vector()
+	camera := Camera.New(d.Camera.Width, d.Camera.Height, origin)
+	if d.Camera.LookAt != nil {
+		up := *Vector.New(0, -1, 0)
+		if d.Camera.Up != nil {
+			up = d.Camera.Up.Vector()
+		}
+		camera.LookAt(origin, d.Camera.LookAt.Vector(), up)
+	} else {
+		rotation := d.Camera.Rotation
+		camera.TranslateCamera(origin)
+		camera.SetOrientation(degreesToRadians(rotation.Yaw), degreesToRadians(rotation.Pitch), degreesToRadians(rotation.Roll))
+	}
+	if d.Camera.FieldOfView > 0 {
+		camera.SetFieldOfView(degreesToRadians(d.Camera.FieldOfView))
+	} else if d.Camera.VerticalFieldOfView > 0 {
+		camera.SetVerticalFieldOfView(degreesToRadians(d.Camera.VerticalFieldOfView))
+	}
+	if d.Camera.Projection != nil && d.Camera.Projection.Type != "perspective" {
+		camera.SetProjection(d.Camera.Projection.build())
+	}
+	if d.Camera.Aperture > 0 {
+		camera.SetDepthOfField(d.Camera.Aperture, d.Camera.FocalDistance)
+	}
+	camera.SetAntiAliasing(d.Camera.AntiAliasing)
+	if d.Camera.Sampler != nil {
+		camera.SetSampler(d.Camera.Sampler.build(d.Camera.AntiAliasing))
+	}
+	if d.Camera.MaxDepth > 0 {
+		camera.SetMaxDepth(d.Camera.MaxDepth)
+	}
+	if d.Camera.ShadowSamples > 0 {
+		camera.SetShadowSamples(d.Camera.ShadowSamples)
+	}
+	if d.Camera.Integrator != "" {
+		integrator, err := Camera.IntegratorByName(d.Camera.Integrator)
+		if err != nil {
+			return nil, &Error{Path: "camera.integrator", Err: err}
+		}
+		camera.SetIntegrator(integrator)
+	}
+
+	for i, description := range d.Objects {
+		object, err := description.build(d.dir)
+		if err != nil {
+			return nil, &Error{Path: fmt.Sprintf("objects[%d]", i), Err: err}
+		}
+		if description.Material != nil {
+			material, err := description.Material.build(description.Color, d.dir)
+			if err != nil {
+				err.Path = fmt.Sprintf("objects[%d].material.%s", i, err.Path)
+				return nil, err
+			}
+			object.(materialSetter).SetMaterial(material)
+		}
+		camera.SetObject(object)
+	}
+
+	for _, description := range d.Lights {
+		camera.SetLight(description.build())
+	}
+
+	if d.Environment != nil {
+		environment, err := d.Environment.build(d.dir)
+		if err != nil {
+			return nil, &Error{Path: "environment.path", Err: err}
+		}
+		camera.SetBackground(environment)
+	}
+
+	return camera, nil
+}
+
+func (o ObjectDescription) build(dir string) (Object.Object, error) {
+	switch o.Type {
+	case "sphere":
+		return Object.NewSphere(o.Center.Vector(), o.Color.Vector(), o.Radius), nil
+	case "plane":
+		return Object.NewPlane(o.Point.Vector(), o.Normal.Vector(), o.Color.Vector()), nil
+	case "box":
+		return Object.NewAABox(o.Min.Vector(), o.Max.Vector(), o.Color.Vector()), nil
+	case "mesh":
+		return Object.LoadOBJ(resolvePath(dir, o.Path), o.Color.Vector())
+	}
+	return nil, fmt.Errorf("unknown object type %q", o.Type)
+}
+
+type materialSetter interface {
+	SetMaterial(material Material.Material)
+}
+
+func (m MaterialDescription) build(diffuse Vec3, dir string) (Material.Material, *Error) {
+	if m.Emission != nil {
+		return Material.NewEmissive(m.Emission.Vector()), nil
+	}
+	if m.IOR > 0 {
+		return Material.NewDielectric(m.IOR, diffuse.Vector()), nil
+	}
+	material := Material.NewPhong(diffuse.Vector(), m.Specular.Vector(), m.Shininess, m.Ambient.Vector())
+	material.SetReflectivity(m.Reflectivity)
+	if m.Texture != nil {
+		texture, err := m.Texture.build(dir)
+		if err != nil {
+			return nil, &Error{Path: "texture.path", Err: err}
+		}
+		material.SetTexture(texture)
+	}
+	if m.NormalMap != nil {
+		image, err := loadDataImage(dir, m.NormalMap.Path, m.NormalMap.Wrap)
+		if err != nil {
+			return nil, &Error{Path: "normalMap.path", Err: err}
+		}
+		strength := 1.0
+		if m.NormalMap.Strength != nil {
+			strength = *m.NormalMap.Strength
+		}
+		material.SetNormalMap(Texture.NewNormalMap(image, strength))
+	}
+	if m.Bump != nil {
+		image, err := loadDataImage(dir, m.Bump.Path, m.Bump.Wrap)
+		if err != nil {
+			return nil, &Error{Path: "bump.path", Err: err}
+		}
+		material.SetNormalMap(Texture.NewBump(image, m.Bump.Scale))
+	}
+	return material, nil
+}
+
+// loadDataImage reads an image of normals or heights, wrap has been validated
+func loadDataImage(dir, path, wrap string) (*Texture.Image, error) {
+	mode, err := wrapMode(wrap)
+	if err != nil {
+		return nil, err
+	}
+	return Texture.LoadDataImage(resolvePath(dir, path), mode)
+}
+
+// wrapMode is repeat when name is empty
+func wrapMode(name string) (Texture.WrapMode, error) {
+	if name == "" {
+		return Texture.Repeat, nil
+	}
+	return Texture.WrapModeByName(name)
+}
+
+// resolvePath makes a path relative to the scene file's directory usable
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func (t TextureDescription) build(dir string) (Texture.Texture, error) {
+	switch t.Type {
+	case "noise":
+		scale, octaves := t.Scale, t.Octaves
+		if scale == 0 {
+			scale = 1
+		}
+		if octaves == 0 {
+			octaves = defaultNoiseOctaves
+		}
+		return Texture.NewNoise(t.Low.Vector(), t.High.Vector(), scale, octaves, t.Seed), nil
+	case "image":
+		wrap, err := wrapMode(t.Wrap)
+		if err != nil {
+			return nil, err
+		}
+		return Texture.LoadImage(resolvePath(dir, t.Path), wrap)
+	}
+	frequency := t.Frequency
+	if frequency == 0 {
+		frequency = 1
+	}
+	return Texture.NewChecker(t.Even.Vector(), t.Odd.Vector(), frequency), nil
+}
+
+func (l LightDescription) build() Light.Light {
+	switch l.Type {
+	case "directional":
+		return Light.NewDirectional(l.Direction.Vector(), l.Color.Vector(), l.Intensity)
+	case "sphere":
+		return Light.NewSphere(l.Position.Vector(), l.Radius, l.Color.Vector(), l.Intensity)
+	case "rect":
+		return Light.NewRect(l.Position.Vector(), l.EdgeU.Vector(), l.EdgeV.Vector(), l.Color.Vector(), l.Intensity)
+	}
+	return Light.NewPoint(l.Position.Vector(), l.Color.Vector(), l.Intensity)
+}
+
+func (e EnvironmentDescription) build(dir string) (*Environment.Map, error) {
+	environment, err := Environment.Load(resolvePath(dir, e.Path))
+	if err != nil {
+		return nil, err
+	}
+	environment.SetRotation(degreesToRadians(e.Rotation))
+	if e.Intensity > 0 {
+		environment.SetIntensity(e.Intensity)
+	}
+	return environment, nil
+}
+
+func (p ProjectionDescription) build() Camera.Projection {
+	switch p.Type {
+	case "orthographic":
+		height := p.Height
+		if height == 0 {
+			height = defaultOrthographicHeight
+		}
+		return Camera.Orthographic{Height: height}
+	case "fisheye":
+		fieldOfView := p.FieldOfView
+		if fieldOfView == 0 {
+			fieldOfView = defaultFisheyeFieldOfView
+		}
+		return Camera.Fisheye{FieldOfView: degreesToRadians(fieldOfView)}
+	case "equirectangular":
+		return Camera.Equirectangular{}
+	}
+	return Camera.Perspective{}
+}
+
+func (s SamplerDescription) build(samplesPerPixel int) Sampler.Sampler {
+	switch s.Type {
+	case "stratified":
+		return Sampler.NewStratified(samplesPerPixel, s.Seed)
+	case "halton":
+		return Sampler.NewHalton(s.Seed)
+	case "sobol":
+		return Sampler.NewSobol(s.Seed)
+	}
+	return Sampler.NewRandom(s.Seed)
+}
+
+func degreesToRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
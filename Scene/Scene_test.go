@@ -0,0 +1,789 @@
+package Scene
+
+import (
+	"errors"
+	"goRay/Camera"
+	"goRay/Environment"
+	"goRay/Light"
+	"goRay/Material"
+	"goRay/Sampler"
+	"goRay/Texture"
+	"goRay/Vector"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDefaultScene(t *testing.T) {
+	description, err := Parse(defaultScene)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	camera, err := description.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(camera.ObjectList) != 4 {
+		t.Errorf("Expected 4 objects, got %d", len(camera.ObjectList))
+	}
+	if pixels := camera.CastRays(); len(pixels) != 100*100 {
+		t.Errorf("Expected a 100x100 image, got %d pixels", len(pixels))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		scene string
+		line  int
+		path  string
+	}{
+		{
+			name: "syntax error",
+			scene: `{
+  "version": 1,
+  "camera": {"width": 10 "height": 10}
+}`,
+			line: 3,
+		},
+		{
+			name: "wrong type",
+			scene: `{
+  "version": 1,
+  "camera": {"width": 10, "height": 10},
+  "objects": [
+    {"type": "sphere", "center": [0, 0, 0], "radius": "big", "color": [1, 1, 1]}
+  ]
+}`,
+			line: 5,
+			path: "objects[0].radius",
+		},
+		{
+			name: "unknown field",
+			scene: `{
+  "version": 1,
+  "camera": {"width": 10, "height": 10, "zoom": 2}
+}`,
+			line: 3,
+			path: "camera.zoom",
+		},
+		{
+			name: "short vector",
+			scene: `{
+  "version": 1,
+  "camera": {"width": 10, "height": 10},
+  "objects": [
+    {"type": "sphere", "center": [0, 0], "radius": 1, "color": [1, 1, 1]}
+  ]
+}`,
+			line: 5,
+			path: "objects[0].center",
+		},
+		{
+			name:  "missing version",
+			scene: `{"camera": {"width": 10, "height": 10}}`,
+			path:  "version",
+		},
+		{
+			name:  "future version",
+			scene: `{"version": 99, "camera": {"width": 10, "height": 10}}`,
+			line:  1,
+			path:  "version",
+		},
+		{
+			name:  "bad resolution",
+			scene: `{"version": 1, "camera": {"width": 0, "height": 10}}`,
+			line:  1,
+			path:  "camera.width",
+		},
+		{
+			name: "bad radius",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [
+  {"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1]},
+  {"type": "sphere", "center": [0, 0, 0], "radius": -1, "color": [1, 1, 1]}
+]}`,
+			line: 3,
+			path: "objects[1].radius",
+		},
+		{
+			name:  "zero plane normal",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "plane", "point": [0, 0, 0], "normal": [0, 0, 0], "color": [1, 1, 1]}]}`,
+			line:  1,
+			path:  "objects[0].normal",
+		},
+		{
+			name:  "box without max",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "box", "min": [0, 0, 0], "color": [1, 1, 1]}]}`,
+			path:  "objects[0].max",
+		},
+		{
+			name:  "lookAt with rotation",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "lookAt": [0, 0, 1], "rotation": {"pitch": 10}}}`,
+			line:  1,
+			path:  "camera.rotation",
+		},
+		{
+			name:  "lookAt at the origin",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "origin": [1, 2, 3], "lookAt": [1, 2, 3]}}`,
+			line:  1,
+			path:  "camera.lookAt",
+		},
+		{
+			name:  "fov too wide",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "fov": 180}}`,
+			line:  1,
+			path:  "camera.fov",
+		},
+		{
+			name:  "both fovs",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "fov": 90, "verticalFov": 60}}`,
+			line:  1,
+			path:  "camera.verticalFov",
+		},
+		{
+			name:  "aperture without focus",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "aperture": 0.5}}`,
+			path:  "camera.focalDistance",
+		},
+		{
+			name:  "unknown projection",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "projection": {"type": "cylindrical"}}}`,
+			line:  1,
+			path:  "camera.projection.type",
+		},
+		{
+			name:  "height on a fisheye",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "projection": {"type": "fisheye", "height": 3}}}`,
+			line:  1,
+			path:  "camera.projection.height",
+		},
+		{
+			name:  "unknown sampler",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "sampler": {"type": "blue noise"}}}`,
+			line:  1,
+			path:  "camera.sampler.type",
+		},
+		{
+			name:  "ior below one",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"ior": 0.5}}]}`,
+			line:  1,
+			path:  "objects[0].material.ior",
+		},
+		{
+			name:  "unknown integrator",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "integrator": "photon map"}}`,
+			line:  1,
+			path:  "camera.integrator",
+		},
+		{
+			name:  "negative emission",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"emission": [1, -1, 1]}}]}`,
+			line:  1,
+			path:  "objects[0].material.emission",
+		},
+		{
+			name:  "glowing glass",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"ior": 1.5, "emission": [1, 1, 1]}}]}`,
+			line:  1,
+			path:  "objects[0].material.emission",
+		},
+		{
+			name:  "light without position",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "lights": [{"type": "point", "color": [1, 1, 1], "intensity": 1}]}`,
+			path:  "lights[0].position",
+		},
+		{
+			name:  "negative light radius",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "lights": [{"type": "sphere", "position": [0, 0, 0], "radius": -1, "color": [1, 1, 1], "intensity": 1}]}`,
+			line:  1,
+			path:  "lights[0].radius",
+		},
+		{
+			name:  "flat rect light",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "lights": [{"type": "rect", "position": [0, 0, 0], "edgeU": [1, 0, 0], "edgeV": [2, 0, 0], "color": [1, 1, 1], "intensity": 1}]}`,
+			line:  1,
+			path:  "lights[0].edgeV",
+		},
+		{
+			name:  "negative shadow samples",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10, "shadowSamples": -4}}`,
+			line:  1,
+			path:  "camera.shadowSamples",
+		},
+		{
+			name:  "environment without path",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "environment": {"intensity": 2}}`,
+			path:  "environment.path",
+		},
+		{
+			name:  "negative environment intensity",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "environment": {"path": "sky.hdr", "intensity": -1}}`,
+			line:  1,
+			path:  "environment.intensity",
+		},
+		{
+			name:  "unknown light",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "lights": [{"type": "spot", "color": [1, 1, 1]}]}`,
+			line:  1,
+			path:  "lights[0].type",
+		},
+		{
+			name:  "short specular",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"specular": [1]}}]}`,
+			line:  1,
+			path:  "objects[0].material.specular",
+		},
+		{
+			name:  "unknown texture",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"texture": {"type": "wood"}}}]}`,
+			line:  1,
+			path:  "objects[0].material.texture.type",
+		},
+		{
+			name:  "checker without colors",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"texture": {"type": "checker", "even": [1, 1, 1]}}}]}`,
+			path:  "objects[0].material.texture.odd",
+		},
+		{
+			name:  "negative noise scale",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"texture": {"type": "noise", "low": [0, 0, 0], "high": [1, 1, 1], "scale": -1}}}]}`,
+			line:  1,
+			path:  "objects[0].material.texture.scale",
+		},
+		{
+			name:  "image without path",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"texture": {"type": "image"}}}]}`,
+			path:  "objects[0].material.texture.path",
+		},
+		{
+			name:  "unknown wrap mode",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"texture": {"type": "image", "path": "a.png", "wrap": "tile"}}}]}`,
+			line:  1,
+			path:  "objects[0].material.texture.wrap",
+		},
+		{
+			name:  "textured glass",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"ior": 1.5, "texture": {"type": "checker", "even": [1, 1, 1], "odd": [0, 0, 0]}}}]}`,
+			line:  1,
+			path:  "objects[0].material.texture",
+		},
+		{
+			name:  "normal map without path",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"normalMap": {"strength": 2}}}]}`,
+			path:  "objects[0].material.normalMap.path",
+		},
+		{
+			name:  "negative normal map strength",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"normalMap": {"path": "a.png", "strength": -1}}}]}`,
+			line:  1,
+			path:  "objects[0].material.normalMap.strength",
+		},
+		{
+			name:  "bump without scale",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"bump": {"path": "a.png"}}}]}`,
+			path:  "objects[0].material.bump.scale",
+		},
+		{
+			name:  "bump with unknown wrap mode",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"bump": {"path": "a.png", "wrap": "tile", "scale": 1}}}]}`,
+			line:  1,
+			path:  "objects[0].material.bump.wrap",
+		},
+		{
+			name:  "bump and normal map",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"normalMap": {"path": "a.png"}, "bump": {"path": "b.png", "scale": 1}}}]}`,
+			line:  1,
+			path:  "objects[0].material.bump",
+		},
+		{
+			name:  "normal mapped light",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "sphere", "center": [0, 0, 0], "radius": 1, "color": [1, 1, 1], "material": {"emission": [1, 1, 1], "normalMap": {"path": "a.png"}}}]}`,
+			line:  1,
+			path:  "objects[0].material.normalMap",
+		},
+		{
+			name:  "unknown object",
+			scene: `{"version": 1, "camera": {"width": 10, "height": 10}, "objects": [{"type": "teapot", "color": [1, 1, 1]}]}`,
+			line:  1,
+			path:  "objects[0].type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.scene))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+
+			var sceneError *Error
+			if !errors.As(err, &sceneError) {
+				t.Fatalf("Expected a scene error, got %T: %v", err, err)
+			}
+			if sceneError.Line != tt.line {
+				t.Errorf("Expected line %d, got %d (%v)", tt.line, sceneError.Line, err)
+			}
+			if sceneError.Path != tt.path {
+				t.Errorf("Expected path %q, got %q (%v)", tt.path, sceneError.Path, err)
+			}
+		})
+	}
+}
+
+func TestCameraOrientation(t *testing.T) {
+	tests := []struct {
+		camera  string
+		forward Vector.Vector
+	}{
+		{
+			camera:  `"rotation": {"yaw": 90}`,
+			forward: *Vector.New(1, 0, 0),
+		},
+		{
+			camera:  `"rotation": {"pitch": 90, "roll": 45}`,
+			forward: *Vector.New(0, -1, 0),
+		},
+		{
+			camera:  `"origin": [1, 1, 1], "lookAt": [1, 1, 5]`,
+			forward: *Vector.New(0, 0, 1),
+		},
+		{
+			camera:  `"lookAt": [0, 3, 4], "up": [0, -1, 0]`,
+			forward: *Vector.New(0, 0.6, 0.8),
+		},
+	}
+	for i, tt := range tests {
+		description, err := Parse([]byte(`{"version": 1, "camera": {"width": 1, "height": 1, ` + tt.camera + `}}`))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		camera, err := description.Build()
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if got := camera.Orient(*Vector.New(0, 0, 1)); got.DistanceBetween(tt.forward) > 1e-9 {
+			t.Errorf("Test %d: Expected to look along %v, got %v", i+1, tt.forward, got)
+		}
+	}
+}
+
+func TestCameraProjection(t *testing.T) {
+	tests := []struct {
+		projection string
+		expected   Camera.Projection
+	}{
+		{projection: ``, expected: Camera.Perspective{}},
+		{projection: `"fov": 90, "projection": {"type": "perspective"}`, expected: Camera.Perspective{VerticalFieldOfView: math.Pi / 2}},
+		{projection: `"projection": {"type": "orthographic"}`, expected: Camera.Orthographic{Height: 50}},
+		{projection: `"projection": {"type": "orthographic", "height": 12}`, expected: Camera.Orthographic{Height: 12}},
+		{projection: `"projection": {"type": "fisheye"}`, expected: Camera.Fisheye{FieldOfView: math.Pi}},
+		{projection: `"projection": {"type": "fisheye", "fov": 90}`, expected: Camera.Fisheye{FieldOfView: math.Pi / 2}},
+		{projection: `"projection": {"type": "equirectangular"}`, expected: Camera.Equirectangular{}},
+	}
+	for i, tt := range tests {
+		description, err := Parse([]byte(`{"version": 1, "camera": {"width": 1, "height": 1` + strings.TrimSuffix(", "+tt.projection, ", ") + `}}`))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		camera, err := description.Build()
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if got := camera.GetProjection(); got != tt.expected {
+			t.Errorf("Test %d: Expected %#v, got %#v", i+1, tt.expected, got)
+		}
+	}
+}
+
+func TestValidateChangedDescription(t *testing.T) {
+	tests := []struct {
+		change func(camera *CameraDescription)
+		path   string
+	}{
+		{change: func(camera *CameraDescription) { camera.FieldOfView = 120 }},
+		{change: func(camera *CameraDescription) { camera.FieldOfView = 180 }, path: "camera.fov"},
+		{change: func(camera *CameraDescription) {
+			camera.Projection = &ProjectionDescription{Type: "fisheye", FieldOfView: 360}
+		}},
+		{change: func(camera *CameraDescription) {
+			camera.Projection = &ProjectionDescription{Type: "fisheye", FieldOfView: 400}
+		}, path: "camera.projection.fov"},
+		{change: func(camera *CameraDescription) {
+			camera.Projection = &ProjectionDescription{Type: "orthographic"}
+			camera.FieldOfView = 60
+		}, path: "camera.fov"},
+	}
+	for i, tt := range tests {
+		description := DefaultDescription()
+		tt.change(&description.Camera)
+		err := description.Validate()
+		if tt.path == "" && err != nil {
+			t.Errorf("Test %d: Expected no error, got %v", i+1, err)
+		}
+		if tt.path != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.path+": ")) {
+			t.Errorf("Test %d: Expected an error naming %s, got %v", i+1, tt.path, err)
+		}
+	}
+}
+
+func TestCameraSampler(t *testing.T) {
+	tests := []struct {
+		sampler  string
+		expected Sampler.Sampler
+	}{
+		{sampler: ``, expected: Sampler.NewRandom(0)},
+		{sampler: `"sampler": {"type": "random", "seed": 4}`, expected: Sampler.NewRandom(4)},
+		{sampler: `"antiAliasing": 9, "sampler": {"type": "stratified", "seed": 2}`, expected: Sampler.NewStratified(9, 2)},
+		{sampler: `"sampler": {"type": "halton", "seed": 3}`, expected: Sampler.NewHalton(3)},
+		{sampler: `"sampler": {"type": "sobol"}`, expected: Sampler.NewSobol(0)},
+	}
+	for i, tt := range tests {
+		description, err := Parse([]byte(`{"version": 1, "camera": {"width": 1, "height": 1` + strings.TrimSuffix(", "+tt.sampler, ", ") + `}}`))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		camera, err := description.Build()
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if got := camera.GetSampler(); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, tt.expected, got)
+		}
+	}
+}
+
+func TestCameraIntegrator(t *testing.T) {
+	tests := []struct {
+		integrator string
+		expected   Camera.Integrator
+	}{
+		{integrator: ``, expected: Camera.Whitted{}},
+		{integrator: `"integrator": "preview"`, expected: Camera.Preview{}},
+		{integrator: `"integrator": "whitted"`, expected: Camera.Whitted{}},
+		{integrator: `"integrator": "path"`, expected: Camera.PathTracer{}},
+	}
+	for i, tt := range tests {
+		description, err := Parse([]byte(`{"version": 1, "camera": {"width": 1, "height": 1` + strings.TrimSuffix(", "+tt.integrator, ", ") + `}}`))
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		camera, err := description.Build()
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if got := camera.GetIntegrator(); got != tt.expected {
+			t.Errorf("Test %d: Expected %T, got %T", i+1, tt.expected, got)
+		}
+	}
+}
+
+func TestEmissiveMaterial(t *testing.T) {
+	description, err := Parse([]byte(`{"version": 1, "camera": {"width": 1, "height": 1}, "objects": [
+		{"type": "sphere", "center": [0, 0, 10], "radius": 1, "color": [1, 0, 0], "material": {"emission": [4, 3, 2]}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	camera, err := description.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	emitter, ok := camera.ObjectList[0].GetMaterial().(Material.Emitter)
+	if !ok {
+		t.Fatalf("Expected an emissive material, got %v", camera.ObjectList[0].GetMaterial())
+	}
+	if emission := emitter.Emission(); emission != *Vector.New(4, 3, 2) {
+		t.Errorf("Expected an emission of 4 3 2, got %v", emission)
+	}
+}
+
+func TestAreaLights(t *testing.T) {
+	description, err := Parse([]byte(`{"version": 1, "camera": {"width": 1, "height": 1, "shadowSamples": 4}, "lights": [
+		{"type": "sphere", "position": [0, -10, 0], "radius": 2, "color": [1, 1, 1], "intensity": 50},
+		{"type": "rect", "position": [-1, -10, -1], "edgeU": [0, 0, 2], "edgeV": [2, 0, 0], "color": [1, 1, 1], "intensity": 50}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	camera, err := description.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if samples := camera.GetShadowSamples(); samples != 4 {
+		t.Errorf("Expected 4 shadow samples, got %d", samples)
+	}
+
+	tests := []struct {
+		area float64
+		// u and v of the sample straight above the origin
+		u, v float64
+	}{
+		{area: 16 * math.Pi, u: 0, v: 0},
+		{area: 4, u: 0.5, v: 0.5},
+	}
+	for i, tt := range tests {
+		light, ok := camera.Lights[i].(Light.AreaLight)
+		if !ok {
+			t.Fatalf("Test %d: Expected an area light, got %v", i+1, camera.Lights[i])
+		}
+		if area := light.Area(); math.Abs(area-tt.area) > 1e-9 {
+			t.Errorf("Test %d: Expected an area of %g, got %g", i+1, tt.area, area)
+		}
+		// both shine down on the origin
+		if direction, _, _ := light.Sample(Vector.Vector{}, tt.u, tt.v); direction != *Vector.New(0, -1, 0) {
+			t.Errorf("Test %d: Expected the light straight above, got %v", i+1, direction)
+		}
+	}
+}
+
+func TestTextures(t *testing.T) {
+	camera, err := Load(filepath.Join("testdata", "textures.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []Texture.Texture{
+		Texture.NewChecker(*Vector.New(0.9, 0.9, 0.9), *Vector.New(0.2, 0.2, 0.25), 0.1),
+		nil,
+		Texture.NewNoise(*Vector.New(0.35, 0.2, 0.1), *Vector.New(0.95, 0.85, 0.7), 0.3, 5, 3),
+	}
+	for i, expected := range tests {
+		textured, ok := camera.ObjectList[i].GetMaterial().(Material.Textured)
+		if !ok || textured.Texture() == nil {
+			t.Fatalf("Test %d: Expected a textured material, got %v", i+1, camera.ObjectList[i].GetMaterial())
+		}
+		if expected != nil && !reflect.DeepEqual(textured.Texture(), expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, expected, textured.Texture())
+		}
+	}
+
+	// the image is resolved next to the scene
+	expected, err := Texture.LoadImage(filepath.Join("testdata", "globe.png"), Texture.Repeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image := camera.ObjectList[1].GetMaterial().(Material.Textured).Texture()
+	if !reflect.DeepEqual(image, expected) {
+		t.Errorf("Expected %v, got %v", expected, image)
+	}
+}
+
+func TestNormalMaps(t *testing.T) {
+	camera, err := Load(filepath.Join("testdata", "normalMaps.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dimples, err := Texture.LoadDataImage(filepath.Join("testdata", "dimples.png"), Texture.Repeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bricks, err := Texture.LoadDataImage(filepath.Join("testdata", "bricks.png"), Texture.Repeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []Texture.Perturbation{
+		nil,
+		Texture.NewNormalMap(dimples, 1),
+		Texture.NewBump(bricks, 0.32),
+	}
+	for i, expected := range tests {
+		mapped, ok := camera.ObjectList[i].GetMaterial().(Material.NormalMapped)
+		if !ok {
+			t.Fatalf("Test %d: Expected a material that can be normal mapped, got %v", i+1, camera.ObjectList[i].GetMaterial())
+		}
+		if !reflect.DeepEqual(mapped.NormalMap(), expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, expected, mapped.NormalMap())
+		}
+	}
+}
+
+func TestNormalMapStrength(t *testing.T) {
+	dimplesPath, err := filepath.Abs(filepath.Join("testdata", "dimples.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dimples, err := Texture.LoadDataImage(dimplesPath, Texture.Repeat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		normalMap string
+		strength  float64
+	}{
+		{normalMap: `{"path": "` + filepath.ToSlash(dimplesPath) + `"}`, strength: 1},
+		{normalMap: `{"path": "` + filepath.ToSlash(dimplesPath) + `", "strength": 0.5}`, strength: 0.5},
+		// 0 is a flat surface rather than the default
+		{normalMap: `{"path": "` + filepath.ToSlash(dimplesPath) + `", "strength": 0}`, strength: 0},
+	}
+
+	for i, tt := range tests {
+		path := filepath.Join(t.TempDir(), "scene.json")
+		scene := `{"version": 1, "camera": {"width": 1, "height": 1}, "objects": [
+  {"type": "sphere", "center": [0, 0, 10], "radius": 1, "color": [1, 1, 1], "material": {"normalMap": ` + tt.normalMap + `}}
+]}`
+		if err := os.WriteFile(path, []byte(scene), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		camera, err := Load(path)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		expected := Texture.NewNormalMap(dimples, tt.strength)
+		if normalMap := camera.ObjectList[0].GetMaterial().(Material.NormalMapped).NormalMap(); !reflect.DeepEqual(normalMap, expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, expected, normalMap)
+		}
+	}
+}
+
+func TestLoadMissingNormalMap(t *testing.T) {
+	tests := []struct {
+		material string
+		path     string
+	}{
+		{material: `{"normalMap": {"path": "missing.png"}}`, path: "objects[0].material.normalMap.path"},
+		{material: `{"bump": {"path": "missing.png", "scale": 1}}`, path: "objects[0].material.bump.path"},
+	}
+
+	for i, tt := range tests {
+		path := filepath.Join(t.TempDir(), "scene.json")
+		scene := `{"version": 1, "camera": {"width": 1, "height": 1}, "objects": [
+  {"type": "sphere", "center": [0, 0, 10], "radius": 1, "color": [1, 1, 1], "material": ` + tt.material + `}
+]}`
+		if err := os.WriteFile(path, []byte(scene), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := Load(path)
+		if err == nil || !strings.Contains(err.Error(), tt.path) {
+			t.Errorf("Test %d: Expected an error naming %s, got %v", i+1, tt.path, err)
+		}
+	}
+}
+
+func TestLoadMissingTexture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scene.json")
+	scene := `{"version": 1, "camera": {"width": 1, "height": 1}, "objects": [
+  {"type": "sphere", "center": [0, 0, 10], "radius": 1, "color": [1, 1, 1], "material": {"texture": {"type": "image", "path": "missing.png"}}}
+]}`
+	if err := os.WriteFile(path, []byte(scene), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "objects[0].material.texture.path") {
+		t.Errorf("Expected an error naming the texture, got %v", err)
+	}
+}
+
+func TestLoadNamesTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(path, []byte("{\n  \"version\": 1,\n  \"camera\": {\"width\": true}\n}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if !strings.HasPrefix(err.Error(), path+": 3:") {
+		t.Errorf("Expected the error to start with the file and line, got %q", err)
+	}
+}
+
+func TestLoadMeshRelativeToScene(t *testing.T) {
+	dir := t.TempDir()
+	obj := "v -1 -1 10\nv 1 -1 10\nv 0 1 10\nf 1 2 3\n"
+	if err := os.WriteFile(filepath.Join(dir, "triangle.obj"), []byte(obj), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scene := `{"version": 1, "camera": {"width": 1, "height": 1}, "objects": [
+  {"type": "mesh", "path": "triangle.obj", "color": [1, 0, 0]}
+]}`
+	path := filepath.Join(dir, "scene.json")
+	if err := os.WriteFile(path, []byte(scene), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	camera, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(camera.ObjectList) != 1 {
+		t.Fatalf("Expected the mesh to be loaded, got %d objects", len(camera.ObjectList))
+	}
+
+	// the triangle winds clockwise as seen from the camera, its back has to be shaded too
+	if r, g, b, _ := camera.CastRays()[0].Color().RGBA(); r == 0 || g != 0 || b != 0 {
+		t.Errorf("Expected the camera to see the red mesh, got %d, %d, %d", r, g, b)
+	}
+}
+
+func TestLoadMissingMesh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scene.json")
+	scene := `{"version": 1, "camera": {"width": 1, "height": 1}, "objects": [
+  {"type": "mesh", "path": "missing.obj", "color": [1, 0, 0]}
+]}`
+	if err := os.WriteFile(path, []byte(scene), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	if err == nil || !strings.HasPrefix(err.Error(), path+": objects[0]") {
+		t.Errorf("Expected an error naming the file and object, got %v", err)
+	}
+
+	// building a description loaded on its own names the file as well
+	description, err := LoadDescription(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := description.Build(); err == nil || !strings.HasPrefix(err.Error(), path+": objects[0]") {
+		t.Errorf("Expected an error naming the file and object, got %v", err)
+	}
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	camera, err := Load(filepath.Join("testdata", "environment.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	background, ok := camera.GetBackground().(*Environment.Map)
+	if !ok {
+		t.Fatalf("Expected an environment map, got %T", camera.GetBackground())
+	}
+
+	expected, err := Environment.Load(filepath.Join("testdata", "sky.hdr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected.SetRotation(degreesToRadians(30))
+	expected.SetIntensity(0.6)
+	for i, direction := range []Vector.Vector{*Vector.New(0, 0, 1), *Vector.New(1, -1, 0), *Vector.New(0, 1, 0)} {
+		if got, want := background.Radiance(direction), expected.Radiance(direction); got != want {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, want, got)
+		}
+	}
+
+	if _, ok := Default().GetBackground().(Camera.Sky); !ok {
+		t.Errorf("Expected the built in scene to keep the sky")
+	}
+}
+
+func TestLoadMissingEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scene.json")
+	scene := `{"version": 1, "camera": {"width": 1, "height": 1}, "environment": {"path": "missing.hdr"}}`
+	if err := os.WriteFile(path, []byte(scene), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "environment.path") {
+		t.Errorf("Expected an error naming the environment, got %v", err)
+	}
+}
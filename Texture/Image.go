@@ -0,0 +1,165 @@
+package Texture
+
+import (
+	"fmt"
+	"goRay/Film"
+	"goRay/Vector"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+)
+
+// WrapMode decides what an image texture shows outside texture coordinates 0 to 1
+type WrapMode int
+
+const (
+	// Repeat tiles the image
+	Repeat WrapMode = iota
+	// Clamp stretches the edge pixels outwards
+	Clamp
+	// Mirror tiles the image flipping every other copy, so the edges meet seamlessly
+	Mirror
+)
+
+// WrapModeNames lists the wrap modes WrapModeByName knows
+var WrapModeNames = []string{"repeat", "clamp", "mirror"}
+
+func WrapModeByName(name string) (WrapMode, error) {
+	switch name {
+	case "repeat":
+		return Repeat, nil
+	case "clamp":
+		return Clamp, nil
+	case "mirror":
+		return Mirror, nil
+	}
+	return 0, fmt.Errorf("unknown wrap mode %q", name)
+}
+
+func (w WrapMode) String() string {
+	if int(w) >= 0 && int(w) < len(WrapModeNames) {
+		return WrapModeNames[w]
+	}
+	return fmt.Sprintf("WrapMode(%d)", int(w))
+}
+
+// wrap maps a pixel index that may lie outside the image to one inside it
+func (w WrapMode) wrap(index, size int) int {
+	switch w {
+	case Clamp:
+		return min(max(index, 0), size-1)
+	case Mirror:
+		index = ((index % (2 * size)) + 2*size) % (2 * size)
+		if index >= size {
+			return 2*size - 1 - index
+		}
+		return index
+	}
+	return ((index % size) + size) % size
+}
+
+// Image maps a picture onto the texture coordinates, u running left to right and v
+// bottom to top as in .obj files. Colors are blended between the four nearest pixels.
+type Image struct {
+	width, height int
+	// pixels holds linear colors in row order from the top
+	pixels []Vector.Vector
+	wrap   WrapMode
+}
+
+// NewImage turns the sRGB colors of img into linear ones
+func NewImage(img image.Image, wrap WrapMode) *Image {
+	return newImage(img, wrap, linear)
+}
+
+// NewDataImage keeps the values of img as stored, from 0 to 1, for images holding
+// data such as normals or heights rather than colors
+func NewDataImage(img image.Image, wrap WrapMode) *Image {
+	return newImage(img, wrap, fraction)
+}
+
+func newImage(img image.Image, wrap WrapMode, decode func(channel uint32) float64) *Image {
+	bounds := img.Bounds()
+	i := &Image{
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		pixels: make([]Vector.Vector, 0, bounds.Dx()*bounds.Dy()),
+		wrap:   wrap,
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			i.pixels = append(i.pixels, *Vector.New(decode(r), decode(g), decode(b)))
+		}
+	}
+	return i
+}
+
+// LoadImage reads a PNG or JPEG image of colors
+func LoadImage(path string, wrap WrapMode) (*Image, error) {
+	img, err := decodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewImage(img, wrap), nil
+}
+
+// LoadDataImage reads a PNG or JPEG image of data, see NewDataImage
+func LoadDataImage(path string, wrap WrapMode) (*Image, error) {
+	img, err := decodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewDataImage(img, wrap), nil
+}
+
+func decodeFile(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return img, nil
+}
+
+func (i *Image) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	if len(i.pixels) == 0 {
+		return Vector.Vector{}
+	}
+
+	// pixel centers sit at half pixels
+	x := u*float64(i.width) - 0.5
+	y := (1-v)*float64(i.height) - 0.5
+	floorX, floorY := math.Floor(x), math.Floor(y)
+	fractionX, fractionY := x-floorX, y-floorY
+	left, top := int(floorX), int(floorY)
+
+	topRow := i.pixel(left, top).Scale(1 - fractionX).Translate(i.pixel(left+1, top).Scale(fractionX))
+	bottomRow := i.pixel(left, top+1).Scale(1 - fractionX).Translate(i.pixel(left+1, top+1).Scale(fractionX))
+	return topRow.Scale(1 - fractionY).Translate(bottomRow.Scale(fractionY))
+}
+
+func (i *Image) pixel(x, y int) Vector.Vector {
+	return i.pixels[i.wrap.wrap(y, i.height)*i.width+i.wrap.wrap(x, i.width)]
+}
+
+func (i *Image) String() string {
+	return fmt.Sprintf("{image: %dx%d, wrap: %s}", i.width, i.height, i.wrap)
+}
+
+// linear turns a 16 bit sRGB channel into linear light
+func linear(channel uint32) float64 {
+	return float64(Film.Linear(float32(channel) / 0xffff))
+}
+
+// fraction turns a 16 bit channel into a value from 0 to 1
+func fraction(channel uint32) float64 {
+	return float64(channel) / 0xffff
+}
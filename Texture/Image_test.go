@@ -0,0 +1,147 @@
+package Texture
+
+import (
+	"goRay/Vector"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// stripes is 4 pixels wide and 2 high, black and white columns alternating and the
+// bottom row at half the brightness of the top, in linear light
+func stripes() *Image {
+	img := image.NewGray(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		if x%2 == 1 {
+			img.SetGray(x, 0, color.Gray{Y: 255})
+			// sRGB 188 is about 0.5 in linear light
+			img.SetGray(x, 1, color.Gray{Y: 188})
+		}
+	}
+	return NewImage(img, Repeat)
+}
+
+func TestWrapModeByName(t *testing.T) {
+	for _, name := range WrapModeNames {
+		wrap, err := WrapModeByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wrap.String() != name {
+			t.Errorf("Expected %q, got %q", name, wrap)
+		}
+	}
+	if _, err := WrapModeByName("tile"); err == nil {
+		t.Error("Expected an error for an unknown wrap mode")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		wrap     WrapMode
+		index    int
+		expected int
+	}{
+		{wrap: Repeat, index: 2, expected: 2},
+		{wrap: Repeat, index: 4, expected: 0},
+		{wrap: Repeat, index: -1, expected: 3},
+		{wrap: Clamp, index: 6, expected: 3},
+		{wrap: Clamp, index: -2, expected: 0},
+		{wrap: Mirror, index: 4, expected: 3},
+		{wrap: Mirror, index: 9, expected: 1},
+		{wrap: Mirror, index: -1, expected: 0},
+		{wrap: Mirror, index: -5, expected: 3},
+	}
+
+	for i, tt := range tests {
+		if index := tt.wrap.wrap(tt.index, 4); index != tt.expected {
+			t.Errorf("Test %d: Expected %s to put %d at %d, got %d", i, tt.wrap, tt.index, tt.expected, index)
+		}
+	}
+}
+
+func TestImageEvaluate(t *testing.T) {
+	img := stripes()
+
+	tests := []struct {
+		u, v     float64
+		expected float64
+	}{
+		// pixel centers, v runs up from the bottom row
+		{u: 0.375, v: 0.75, expected: 1},
+		{u: 0.125, v: 0.75, expected: 0},
+		{u: 0.375, v: 0.25, expected: 0.5},
+		// halfway between a black and a white pixel
+		{u: 0.25, v: 0.75, expected: 0.5},
+		// halfway between the rows
+		{u: 0.375, v: 0.5, expected: 0.75},
+		// the left edge blends in the rightmost column when repeating
+		{u: 0, v: 0.75, expected: 0.5},
+		{u: 1.375, v: 0.75, expected: 1},
+	}
+
+	for i, tt := range tests {
+		color := img.Evaluate(tt.u, tt.v, Vector.Vector{})
+		if math.Abs(color.X()-tt.expected) > 0.01 || color.X() != color.Y() || color.Y() != color.Z() {
+			t.Errorf("Test %d: Expected %g at %g, %g, got %v", i, tt.expected, tt.u, tt.v, color)
+		}
+	}
+
+	// clamping keeps the black edge column
+	clamped := NewImage(image.NewGray(image.Rect(0, 0, 1, 1)), Clamp)
+	if color := clamped.Evaluate(-3, 5, Vector.Vector{}); color != black {
+		t.Errorf("Expected black, got %v", color)
+	}
+}
+
+func TestLoadImage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "texture.png")
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	texture, err := LoadImage(path, Clamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the top left pixel is red
+	if color := texture.Evaluate(0.25, 0.75, Vector.Vector{}); color != *Vector.New(1, 0, 0) {
+		t.Errorf("Expected red, got %v", color)
+	}
+	if color := texture.Evaluate(0.75, 0.25, Vector.Vector{}); color != white {
+		t.Errorf("Expected white, got %v", color)
+	}
+
+	if _, err := LoadImage(filepath.Join(t.TempDir(), "missing.png"), Repeat); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestDataImageKeepsValues(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 1, 1))
+	img.SetGray(0, 0, color.Gray{Y: 128})
+
+	if value := NewDataImage(img, Repeat).Evaluate(0.5, 0.5, Vector.Vector{}); math.Abs(value.X()-128.0/255) > 1e-9 {
+		t.Errorf("Expected the stored %g, got %v", 128.0/255, value)
+	}
+	// as a color the same pixel is darker in linear light
+	if value := NewImage(img, Repeat).Evaluate(0.5, 0.5, Vector.Vector{}); math.Abs(value.X()-0.2158) > 0.001 {
+		t.Errorf("Expected the linear 0.2158, got %v", value)
+	}
+}
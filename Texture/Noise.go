@@ -0,0 +1,129 @@
+package Texture
+
+import (
+	"fmt"
+	"goRay/Vector"
+	"math"
+	"math/rand"
+)
+
+// Perlin is Ken Perlin's improved gradient noise, a smooth random function of space
+// that is 0 at every integer lattice point and stays roughly between -1 and 1
+type Perlin struct {
+	// permutation is a shuffle of 0 to 255 repeated twice, so lookups needn't wrap
+	permutation [512]int
+}
+
+// NewPerlin shuffles the lattice with seed, the same seed always gives the same noise
+func NewPerlin(seed int64) *Perlin {
+	p := &Perlin{}
+	shuffled := rand.New(rand.NewSource(seed)).Perm(256)
+	for i := range p.permutation {
+		p.permutation[i] = shuffled[i%256]
+	}
+	return p
+}
+
+// Noise returns the noise at a point
+func (p *Perlin) Noise(point Vector.Vector) float64 {
+	x, y, z := point.X(), point.Y(), point.Z()
+	floorX, floorY, floorZ := math.Floor(x), math.Floor(y), math.Floor(z)
+	// the lattice cell and the position within it
+	cellX, cellY, cellZ := int(floorX)&255, int(floorY)&255, int(floorZ)&255
+	x, y, z = x-floorX, y-floorY, z-floorZ
+	u, v, w := fade(x), fade(y), fade(z)
+
+	perm := &p.permutation
+	a := perm[cellX] + cellY
+	aa, ab := perm[a]+cellZ, perm[a+1]+cellZ
+	b := perm[cellX+1] + cellY
+	ba, bb := perm[b]+cellZ, perm[b+1]+cellZ
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, gradient(perm[aa], x, y, z), gradient(perm[ba], x-1, y, z)),
+			lerp(u, gradient(perm[ab], x, y-1, z), gradient(perm[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, gradient(perm[aa+1], x, y, z-1), gradient(perm[ba+1], x-1, y, z-1)),
+			lerp(u, gradient(perm[ab+1], x, y-1, z-1), gradient(perm[bb+1], x-1, y-1, z-1))))
+}
+
+// FBM adds octaves of noise, each at twice the frequency and half the amplitude of
+// the one before, and scales the sum back to between -1 and 1
+func (p *Perlin) FBM(point Vector.Vector, octaves int) float64 {
+	sum, amplitude, total := 0.0, 1.0, 0.0
+	for octave := 0; octave < octaves; octave++ {
+		sum += amplitude * p.Noise(point)
+		total += amplitude
+		point = point.Scale(2)
+		amplitude /= 2
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// fade is 6t^5 - 15t^4 + 10t^3, which eases in and out of every lattice cell with
+// continuous first and second derivatives
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// gradient dots the offset from a lattice point with one of 12 edge directions of a
+// cube, picked by the low bits of hash
+func gradient(hash int, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// Noise is a solid texture blending two colors by fractal noise through space, for
+// marble, clouds or rough ground. It doesn't need texture coordinates.
+type Noise struct {
+	perlin    *Perlin
+	low, high Vector.Vector
+	scale     float64
+	octaves   int
+}
+
+// NewNoise takes the colors of the lowest and highest noise and how many noise
+// features fit in a unit of space
+func NewNoise(low, high Vector.Vector, scale float64, octaves int, seed int64) *Noise {
+	return &Noise{
+		perlin:  NewPerlin(seed),
+		low:     low,
+		high:    high,
+		scale:   scale,
+		octaves: octaves,
+	}
+}
+
+func (n *Noise) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	t := 0.5 + 0.5*n.perlin.FBM(point.Scale(n.scale), n.octaves)
+	t = math.Max(0, math.Min(1, t))
+	return n.low.Scale(1 - t).Translate(n.high.Scale(t))
+}
+
+func (n *Noise) String() string {
+	return fmt.Sprintf("{noise: %s %s, scale: %g, octaves: %d}", n.low, n.high, n.scale, n.octaves)
+}
@@ -0,0 +1,81 @@
+package Texture
+
+import (
+	"goRay/Vector"
+	"math"
+	"testing"
+)
+
+func TestPerlinIsZeroOnTheLattice(t *testing.T) {
+	perlin := NewPerlin(1)
+	for i, point := range []Vector.Vector{{}, *Vector.New(1, 2, 3), *Vector.New(-4, 7, -300)} {
+		if noise := perlin.Noise(point); noise != 0 {
+			t.Errorf("Test %d: Expected 0 at %v, got %g", i, point, noise)
+		}
+	}
+}
+
+func TestPerlinIsSmooth(t *testing.T) {
+	perlin := NewPerlin(7)
+	low, high, largestStep := math.Inf(1), math.Inf(-1), 0.0
+	previous := perlin.Noise(*Vector.New(0, 0.3, 0.7))
+	for i := 1; i < 10000; i++ {
+		noise := perlin.Noise(*Vector.New(float64(i)*0.001, 0.3, 0.7))
+		low, high = math.Min(low, noise), math.Max(high, noise)
+		largestStep = math.Max(largestStep, math.Abs(noise-previous))
+		previous = noise
+	}
+
+	if low < -1 || high > 1 {
+		t.Errorf("Expected the noise to stay between -1 and 1, got %g to %g", low, high)
+	}
+	if high-low < 0.3 {
+		t.Errorf("Expected the noise to vary, got %g to %g", low, high)
+	}
+	// the gradients are at most 2 long and fade has a slope of at most 1.875
+	if largestStep > 0.01 {
+		t.Errorf("Expected small steps between close points, got %g", largestStep)
+	}
+}
+
+func TestPerlinSeed(t *testing.T) {
+	point := *Vector.New(0.4, 1.7, -2.3)
+	if NewPerlin(3).Noise(point) != NewPerlin(3).Noise(point) {
+		t.Error("Expected the same seed to give the same noise")
+	}
+	if NewPerlin(3).Noise(point) == NewPerlin(4).Noise(point) {
+		t.Error("Expected different seeds to give different noise")
+	}
+}
+
+func TestFBM(t *testing.T) {
+	perlin := NewPerlin(2)
+	point := *Vector.New(0.4, 1.7, -2.3)
+	if perlin.FBM(point, 1) != perlin.Noise(point) {
+		t.Error("Expected a single octave to be plain noise")
+	}
+	if fbm := perlin.FBM(point, 0); fbm != 0 {
+		t.Errorf("Expected no octaves to give 0, got %g", fbm)
+	}
+
+	expected := (perlin.Noise(point) + perlin.Noise(point.Scale(2))/2 + perlin.Noise(point.Scale(4))/4) / 1.75
+	if fbm := perlin.FBM(point, 3); math.Abs(fbm-expected) > 1e-12 {
+		t.Errorf("Expected %g, got %g", expected, fbm)
+	}
+}
+
+func TestNoiseTextureBlendsColors(t *testing.T) {
+	noise := NewNoise(black, white, 0.5, 4, 5)
+	for i := 0; i < 1000; i++ {
+		point := *Vector.New(float64(i)*0.37, float64(i%13)*0.91, float64(i%7)*-1.3)
+		color := noise.Evaluate(0, 0, point)
+		if color.X() < 0 || color.X() > 1 || color.X() != color.Y() || color.Y() != color.Z() {
+			t.Fatalf("Expected a grey between black and white at %v, got %v", point, color)
+		}
+	}
+
+	// on the lattice the noise is 0, halfway between the colors
+	if color := noise.Evaluate(0.3, 0.9, *Vector.New(2, 4, 6)); color != *Vector.New(0.5, 0.5, 0.5) {
+		t.Errorf("Expected the middle grey, got %v", color)
+	}
+}
@@ -0,0 +1,91 @@
+package Texture
+
+import (
+	"fmt"
+	"goRay/Vector"
+	"math"
+)
+
+// Perturbation bends the normal of a surface to show detail its geometry doesn't have
+type Perturbation interface {
+	// Normal returns the bent normal in tangent space at texture coordinates u and v,
+	// x pointing the way u grows, y the way v grows and z along the unbent normal
+	Normal(u, v float64, point Vector.Vector) Vector.Vector
+}
+
+// NormalMap reads tangent space normals from an image, red holding x, green y and
+// blue z, each stored from 0 to 1 for -1 to 1. A flat map is 0.5, 0.5, 1.
+type NormalMap struct {
+	image    *Image
+	strength float64
+}
+
+// NewNormalMap takes an image loaded as data and how strongly to tilt the normals, 1
+// tilting them as stored and 0 leaving the surface flat
+func NewNormalMap(image *Image, strength float64) *NormalMap {
+	return &NormalMap{image: image, strength: strength}
+}
+
+func (n *NormalMap) Normal(u, v float64, point Vector.Vector) Vector.Vector {
+	stored := n.image.Evaluate(u, v, point)
+	x, y, z := 2*stored.X()-1, 2*stored.Y()-1, 2*stored.Z()-1
+	normal := *Vector.New(x*n.strength, y*n.strength, math.Max(z, 0))
+	if normal == (Vector.Vector{}) {
+		return *Vector.New(0, 0, 1)
+	}
+	return normal.Normalize()
+}
+
+func (n *NormalMap) String() string {
+	return fmt.Sprintf("{normal map: %s, strength: %g}", n.image, n.strength)
+}
+
+// HeightMap is a perturbation that raises the surface along its normal. Its slopes
+// are steeper where the texture is stretched less over the surface, so rather than
+// the tangent space normal shading asks it for the slopes and tilts the normal by how
+// far the surface moves with u and v.
+type HeightMap interface {
+	Perturbation
+	// Slopes returns how much the height grows per unit of u and per unit of v
+	Slopes(u, v float64, point Vector.Vector) (float64, float64)
+}
+
+// bumpDelta is how far apart in texture coordinates a bump map compares heights
+const bumpDelta = 1e-3
+
+// Bump treats the brightness of a texture as the height of the surface and tilts the
+// normal down its slopes. The heights are looked up by texture coordinates, so solid
+// textures, which don't vary with them, leave the surface flat.
+type Bump struct {
+	height Texture
+	scale  float64
+}
+
+// NewBump takes the texture holding the heights and how high its brightest part
+// stands over its darkest, in the units of the scene
+func NewBump(height Texture, scale float64) *Bump {
+	return &Bump{height: height, scale: scale}
+}
+
+func (b *Bump) Slopes(u, v float64, point Vector.Vector) (float64, float64) {
+	slopeU := (b.heightAt(u+bumpDelta, v, point) - b.heightAt(u-bumpDelta, v, point)) / (2 * bumpDelta)
+	slopeV := (b.heightAt(u, v+bumpDelta, point) - b.heightAt(u, v-bumpDelta, point)) / (2 * bumpDelta)
+	return b.scale * slopeU, b.scale * slopeV
+}
+
+// Normal is the bent normal on a surface where one unit of u and of v is one unit of
+// distance, use Slopes for any other
+func (b *Bump) Normal(u, v float64, point Vector.Vector) Vector.Vector {
+	slopeU, slopeV := b.Slopes(u, v, point)
+	return Vector.New(-slopeU, -slopeV, 1).Normalize()
+}
+
+// heightAt is the average of the texture's channels
+func (b *Bump) heightAt(u, v float64, point Vector.Vector) float64 {
+	color := b.height.Evaluate(u, v, point)
+	return (color.X() + color.Y() + color.Z()) / 3
+}
+
+func (b *Bump) String() string {
+	return fmt.Sprintf("{bump: %v, scale: %g}", b.height, b.scale)
+}
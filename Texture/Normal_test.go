@@ -0,0 +1,86 @@
+package Texture
+
+import (
+	"goRay/Vector"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// normalImage is a single pixel normal map storing normal
+func normalImage(normal Vector.Vector) *Image {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{
+		R: uint8(math.Round((normal.X() + 1) / 2 * 255)),
+		G: uint8(math.Round((normal.Y() + 1) / 2 * 255)),
+		B: uint8(math.Round((normal.Z() + 1) / 2 * 255)),
+		A: 255,
+	})
+	return NewDataImage(img, Repeat)
+}
+
+func TestNormalMap(t *testing.T) {
+	tilted := Vector.New(0.6, 0, 0.8).Normalize()
+
+	tests := []struct {
+		stored   Vector.Vector
+		strength float64
+		expected Vector.Vector
+	}{
+		{stored: *Vector.New(0, 0, 1), strength: 1, expected: *Vector.New(0, 0, 1)},
+		{stored: tilted, strength: 1, expected: tilted},
+		{stored: tilted, strength: 0, expected: *Vector.New(0, 0, 1)},
+		// twice the strength doubles the slope
+		{stored: tilted, strength: 2, expected: Vector.New(1.2, 0, 0.8).Normalize()},
+		{stored: Vector.New(0, -0.6, 0.8).Normalize(), strength: 1, expected: *Vector.New(0, -0.6, 0.8)},
+	}
+
+	for i, tt := range tests {
+		normal := NewNormalMap(normalImage(tt.stored), tt.strength).Normal(0.5, 0.5, Vector.Vector{})
+		if normal.DistanceBetween(tt.expected) > 0.01 {
+			t.Errorf("Test %d: Expected %v, got %v", i, tt.expected, normal)
+		}
+		if length := math.Sqrt(normal.Dot(normal)); math.Abs(length-1) > 1e-9 {
+			t.Errorf("Test %d: Expected a unit normal, got a length of %g", i, length)
+		}
+	}
+}
+
+// ramp is a height rising by slope per unit of u
+type ramp struct {
+	slope float64
+}
+
+func (r ramp) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	return *Vector.New(r.slope*u, r.slope*u, r.slope*u)
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		height   Texture
+		scale    float64
+		expected Vector.Vector
+	}{
+		{height: Constant{Color: white}, scale: 5, expected: *Vector.New(0, 0, 1)},
+		// a slope rising along u tilts the normal back towards -u
+		{height: ramp{slope: 1}, scale: 1, expected: Vector.New(-1, 0, 1).Normalize()},
+		{height: ramp{slope: 0.5}, scale: 4, expected: Vector.New(-2, 0, 1).Normalize()},
+		// solid textures don't vary with the texture coordinates
+		{height: NewNoise(black, white, 1, 4, 1), scale: 1, expected: *Vector.New(0, 0, 1)},
+	}
+
+	for i, tt := range tests {
+		bump := NewBump(tt.height, tt.scale)
+		normal := bump.Normal(0.3, 0.6, *Vector.New(0.25, 0.5, 0.75))
+		if normal.DistanceBetween(tt.expected) > 1e-6 {
+			t.Errorf("Test %d: Expected %v, got %v", i, tt.expected, normal)
+		}
+
+		// the slopes are the tilt of the normal on a surface of unit tangents
+		slopeU, slopeV := bump.Slopes(0.3, 0.6, *Vector.New(0.25, 0.5, 0.75))
+		if math.Abs(slopeU+tt.expected.X()/tt.expected.Z()) > 1e-6 || math.Abs(slopeV+tt.expected.Y()/tt.expected.Z()) > 1e-6 {
+			t.Errorf("Test %d: Expected slopes matching %v, got %g and %g", i, tt.expected, slopeU, slopeV)
+		}
+	}
+}
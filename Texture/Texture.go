@@ -0,0 +1,46 @@
+package Texture
+
+import (
+	"fmt"
+	"goRay/Vector"
+	"math"
+)
+
+// Texture gives the color of a surface at a hit point, from the point's texture
+// coordinates u and v or from the point itself for solid textures
+type Texture interface {
+	Evaluate(u, v float64, point Vector.Vector) Vector.Vector
+}
+
+// Constant is the same color everywhere
+type Constant struct {
+	Color Vector.Vector
+}
+
+func (c Constant) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	return c.Color
+}
+
+// Checker alternates two colors in squares laid out on the texture coordinates
+type Checker struct {
+	even, odd Vector.Vector
+	frequency float64
+}
+
+// NewChecker takes how many squares fit in one unit of u and v, the square at the
+// origin being even
+func NewChecker(even, odd Vector.Vector, frequency float64) *Checker {
+	return &Checker{even: even, odd: odd, frequency: frequency}
+}
+
+func (c *Checker) Evaluate(u, v float64, point Vector.Vector) Vector.Vector {
+	square := int(math.Floor(u*c.frequency)) + int(math.Floor(v*c.frequency))
+	if square%2 == 0 {
+		return c.even
+	}
+	return c.odd
+}
+
+func (c *Checker) String() string {
+	return fmt.Sprintf("{checker: %s %s, frequency: %g}", c.even, c.odd, c.frequency)
+}
@@ -0,0 +1,43 @@
+package Texture
+
+import (
+	"goRay/Vector"
+	"testing"
+)
+
+var (
+	black = Vector.Vector{}
+	white = *Vector.New(1, 1, 1)
+)
+
+func TestConstant(t *testing.T) {
+	constant := Constant{Color: *Vector.New(0.2, 0.4, 0.6)}
+	if color := constant.Evaluate(3, -7, *Vector.New(1, 2, 3)); color != *Vector.New(0.2, 0.4, 0.6) {
+		t.Errorf("Expected the constant color, got %v", color)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker(white, black, 2)
+
+	tests := []struct {
+		u, v     float64
+		expected Vector.Vector
+	}{
+		{u: 0.1, v: 0.1, expected: white},
+		{u: 0.6, v: 0.1, expected: black},
+		{u: 0.1, v: 0.6, expected: black},
+		{u: 0.6, v: 0.6, expected: white},
+		{u: 1.1, v: 0.1, expected: white},
+		// squares keep alternating across 0
+		{u: -0.1, v: 0.1, expected: black},
+		{u: -0.1, v: -0.1, expected: white},
+		{u: -0.6, v: 0.1, expected: white},
+	}
+
+	for i, tt := range tests {
+		if color := checker.Evaluate(tt.u, tt.v, Vector.Vector{}); color != tt.expected {
+			t.Errorf("Test %d: Expected %v at %g, %g, got %v", i, tt.expected, tt.u, tt.v, color)
+		}
+	}
+}
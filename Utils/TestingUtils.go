@@ -1,2 +1,90 @@
 package Utils
 
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// diffGain scales differences up in diff images so small ones are still visible
+const diffGain = 8
+
+// RMSE returns the root mean square difference of the red, green and blue channels of
+// two images of the same size, from 0 for identical images to 1 for black against white
+func RMSE(a, b image.Image) (float64, error) {
+	if a.Bounds().Size() != b.Bounds().Size() {
+		return 0, fmt.Errorf("image sizes differ, %v and %v", a.Bounds().Size(), b.Bounds().Size())
+	}
+
+	size := a.Bounds().Size()
+	if size.X == 0 || size.Y == 0 {
+		return 0, nil
+	}
+	sum := 0.0
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			r1, g1, b1 := channels(a, x, y)
+			r2, g2, b2 := channels(b, x, y)
+			sum += (r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2)
+		}
+	}
+	return math.Sqrt(sum / float64(size.X*size.Y*3)), nil
+}
+
+// DiffImage shows where two images of the same size differ, black where they match and
+// brighter the more a channel differs. Parts only one image covers are left black.
+func DiffImage(a, b image.Image) *image.RGBA {
+	size := a.Bounds().Size()
+	diff := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			diff.SetRGBA(x, y, color.RGBA{A: 255})
+			if !image.Pt(x, y).Add(b.Bounds().Min).In(b.Bounds()) {
+				continue
+			}
+			r1, g1, b1 := channels(a, x, y)
+			r2, g2, b2 := channels(b, x, y)
+			diff.SetRGBA(x, y, color.RGBA{R: diffChannel(r1, r2), G: diffChannel(g1, g2), B: diffChannel(b1, b2), A: 255})
+		}
+	}
+	return diff
+}
+
+// channels returns the color at x, y from the top left of the image, each channel 0 to 1
+func channels(img image.Image, x, y int) (float64, float64, float64) {
+	r, g, b, _ := img.At(img.Bounds().Min.X+x, img.Bounds().Min.Y+y).RGBA()
+	return float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff
+}
+
+func diffChannel(a, b float64) uint8 {
+	return uint8(math.Min(1, math.Abs(a-b)*diffGain) * 255)
+}
+
+func ReadPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return png.Decode(file)
+}
+
+// WritePNG creates the directory of path if it doesn't exist yet
+func WritePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,91 @@
+package Utils
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func uniform(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestRMSE(t *testing.T) {
+	black := color.RGBA{A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	halfChanged := uniform(2, 1, black)
+	halfChanged.SetRGBA(1, 0, color.RGBA{R: 255, A: 255})
+
+	tests := []struct {
+		a, b     image.Image
+		expected float64
+		wantErr  bool
+	}{
+		{a: uniform(4, 3, white), b: uniform(4, 3, white), expected: 0},
+		{a: uniform(4, 3, black), b: uniform(4, 3, white), expected: 1},
+		// one channel of one of two pixels is a sixth of all channels
+		{a: uniform(2, 1, black), b: halfChanged, expected: math.Sqrt(1.0 / 6)},
+		{a: uniform(4, 3, black), b: uniform(3, 4, black), wantErr: true},
+	}
+	for i, tt := range tests {
+		got, err := RMSE(tt.a, tt.b)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Test %d: unexpected error state: %v", i+1, err)
+			continue
+		}
+		if math.Abs(got-tt.expected) > 1e-12 {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, tt.expected, got)
+		}
+	}
+}
+
+func TestRMSEIgnoresOrigin(t *testing.T) {
+	a := uniform(4, 4, color.RGBA{R: 10, A: 255})
+	b := uniform(6, 6, color.RGBA{A: 255}).SubImage(image.Rect(2, 2, 6, 6))
+	b.(*image.RGBA).SetRGBA(2, 2, color.RGBA{R: 10, A: 255})
+
+	got, err := RMSE(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := math.Sqrt(15 * math.Pow(10.0/255, 2) / 48); math.Abs(got-expected) > 1e-12 {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestDiffImage(t *testing.T) {
+	a := uniform(2, 1, color.RGBA{R: 100, G: 50, A: 255})
+	b := uniform(2, 1, color.RGBA{R: 100, G: 50, A: 255})
+	b.SetRGBA(1, 0, color.RGBA{R: 110, G: 200, B: 1, A: 255})
+
+	diff := DiffImage(a, b)
+	if got := diff.RGBAAt(0, 0); got != (color.RGBA{A: 255}) {
+		t.Errorf("Expected matching pixels to be black, got %v", got)
+	}
+	if got := diff.RGBAAt(1, 0); got.R != 80 || got.G != 255 || got.B != 8 {
+		t.Errorf("Expected the difference scaled by %d, got %v", diffGain, got)
+	}
+}
+
+func TestPNGRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "image.png")
+	img := uniform(3, 2, color.RGBA{R: 1, G: 2, B: 3, A: 255})
+	if err := WritePNG(path, img); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadPNG(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rmse, _ := RMSE(img, read); rmse != 0 {
+		t.Errorf("Expected the image to survive the round trip, RMSE was %v", rmse)
+	}
+}
@@ -69,6 +69,53 @@ func RotateYBuilder(radAngle float64) func(v Vector) Vector {
 	}
 }
 
+// RotateBuilder returns a rotation by roll about z, then pitch about x, then yaw about y.
+// Positive pitch tips +z towards -y, with only yaw set it matches RotateYBuilder.
+func RotateBuilder(yaw, pitch, roll float64) func(v Vector) Vector {
+	cosYaw, sinYaw := math.Cos(yaw), math.Sin(yaw)
+	cosPitch, sinPitch := math.Cos(pitch), math.Sin(pitch)
+	cosRoll, sinRoll := math.Cos(roll), math.Sin(roll)
+
+	yawMatrix := matrix{
+		{cosYaw, 0, sinYaw},
+		{0, 1, 0},
+		{-sinYaw, 0, cosYaw},
+	}
+	pitchMatrix := matrix{
+		{1, 0, 0},
+		{0, cosPitch, -sinPitch},
+		{0, sinPitch, cosPitch},
+	}
+	rollMatrix := matrix{
+		{cosRoll, -sinRoll, 0},
+		{sinRoll, cosRoll, 0},
+		{0, 0, 1},
+	}
+	return yawMatrix.multiply(pitchMatrix).multiply(rollMatrix).apply
+}
+
+type matrix [3][3]float64
+
+func (m matrix) multiply(m2 matrix) matrix {
+	var product matrix
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			for i := 0; i < 3; i++ {
+				product[row][col] += m[row][i] * m2[i][col]
+			}
+		}
+	}
+	return product
+}
+
+func (m matrix) apply(v Vector) Vector {
+	return Vector{
+		x: m[0][0]*v.x + m[0][1]*v.y + m[0][2]*v.z,
+		y: m[1][0]*v.x + m[1][1]*v.y + m[1][2]*v.z,
+		z: m[2][0]*v.x + m[2][1]*v.y + m[2][2]*v.z,
+	}
+}
+
 func (v Vector) Normalize() Vector {
 	magnitude := v.magnitude()
 	if magnitude != 0 {
@@ -104,3 +151,45 @@ func (v Vector) DistanceBetween(vector Vector) float64 {
 }
 
 
+func (v Vector) Cross(v2 Vector) Vector {
+	return Vector{
+		x: v.y*v2.z - v.z*v2.y,
+		y: v.z*v2.x - v.x*v2.z,
+		z: v.x*v2.y - v.y*v2.x,
+	}
+}
+
+// Reflect mirrors the vector about normal, which must be unit length
+func (v Vector) Reflect(normal Vector) Vector {
+	return v.Minus(normal.Scale(2 * v.Dot(normal)))
+}
+
+// Refract bends the unit vector through a surface using Snell's law. normal must
+// be unit length and face against the vector, eta is the ratio of the refractive
+// indices (incoming over outgoing). It returns false on total internal reflection.
+func (v Vector) Refract(normal Vector, eta float64) (Vector, bool) {
+	cosIncident := -v.Dot(normal)
+	sinTransmitted2 := eta * eta * (1 - cosIncident*cosIncident)
+	if sinTransmitted2 > 1 {
+		return Vector{}, false
+	}
+	cosTransmitted := math.Sqrt(1 - sinTransmitted2)
+	return v.Scale(eta).Translate(normal.Scale(eta*cosIncident - cosTransmitted)), true
+}
+
+// OrthonormalBasis returns two unit vectors perpendicular to the unit vector and each
+// other, which together with it make a frame to place directions around it
+func (v Vector) OrthonormalBasis() (Vector, Vector) {
+	helper := Vector{x: 1}
+	if math.Abs(v.x) > 0.9 {
+		helper = Vector{y: 1}
+	}
+	tangent := v.Cross(helper).Normalize()
+	return tangent, v.Cross(tangent)
+}
+
+// Multiply scales each component by the matching component of vector, used to filter colors
+func (v Vector) Multiply(vector Vector) Vector {
+	return *New(v.x*vector.x, v.y*vector.y, v.z*vector.z)
+}
+
@@ -146,6 +146,42 @@ func TestVector_Rotate(t *testing.T) {
 	}
 }
 
+func TestRotateBuilder(t *testing.T) {
+	quarterTurn := math.Pi / 2
+	tests := []struct {
+		yaw, pitch, roll float64
+		vector           Vector
+		expected         Vector
+	}{
+		{yaw: quarterTurn, vector: *New(0, 0, 1), expected: *New(1, 0, 0)},
+		{pitch: quarterTurn, vector: *New(0, 0, 1), expected: *New(0, -1, 0)},
+		{roll: quarterTurn, vector: *New(1, 0, 0), expected: *New(0, 1, 0)},
+		// roll is applied first, then pitch, then yaw
+		{yaw: quarterTurn, pitch: quarterTurn, vector: *New(0, 0, 1), expected: *New(0, -1, 0)},
+		{yaw: quarterTurn, roll: quarterTurn, vector: *New(1, 0, 0), expected: *New(0, 1, 0)},
+		{yaw: quarterTurn, pitch: quarterTurn, roll: quarterTurn, vector: *New(0, 1, 0), expected: *New(0, 0, 1)},
+	}
+	for i, test := range tests {
+		got := RotateBuilder(test.yaw, test.pitch, test.roll)(test.vector)
+		if !VectorIsEqual(&got, &test.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, test.expected, got)
+		}
+	}
+}
+
+func TestRotateBuilderMatchesYawOnly(t *testing.T) {
+	vectors := []Vector{*New(0, 0, 1), *New(1, 2, 3), *New(-0.5, 0.25, -4)}
+	for i, angle := range []float64{0, 0.3, math.Pi / 32, -2, math.Pi} {
+		rotate := RotateBuilder(angle, 0, 0)
+		rotateY := RotateYBuilder(angle)
+		for _, v := range vectors {
+			if got, want := rotate(v), rotateY(v); got != want {
+				t.Errorf("Test %d: expected %v, got %v", i+1, want, got)
+			}
+		}
+	}
+}
+
 
 func Benchmark_Normalize(b *testing.B) {
 	v := New(2,4,1)
@@ -229,4 +265,142 @@ func TestVector_DistanceBetween(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestVector_Cross(t *testing.T) {
+	tests := []struct {
+		v1     Vector
+		v2     Vector
+		result Vector
+	}{
+		{
+			v1:     *New(1, 0, 0),
+			v2:     *New(0, 1, 0),
+			result: *New(0, 0, 1),
+		}, {
+			v1:     *New(0, 1, 0),
+			v2:     *New(1, 0, 0),
+			result: *New(0, 0, -1),
+		}, {
+			v1:     *New(2, 3, 4),
+			v2:     *New(5, 6, 7),
+			result: *New(-3, 6, -3),
+		},
+	}
+	for i, test := range tests {
+		if test.v1.Cross(test.v2) != test.result {
+			t.Errorf("test %d: expected %v, got %v", i, test.result, test.v1.Cross(test.v2))
+		}
+	}
+}
+
+func TestVector_Reflect(t *testing.T) {
+	tests := []struct {
+		v      Vector
+		normal Vector
+		result Vector
+	}{
+		{
+			v:      *New(0, 0, 1),
+			normal: *New(0, 0, -1),
+			result: *New(0, 0, -1),
+		}, {
+			v:      *New(1, 1, 0),
+			normal: *New(0, -1, 0),
+			result: *New(1, -1, 0),
+		}, {
+			v:      *New(1, 1, 0),
+			normal: *New(1, 0, 0),
+			result: *New(-1, 1, 0),
+		},
+	}
+	for i, test := range tests {
+		if got := test.v.Reflect(test.normal); !VectorIsEqual(&got, &test.result) {
+			t.Errorf("test %d: expected %v, got %v", i, test.result, got)
+		}
+	}
+}
+
+func TestVector_Refract(t *testing.T) {
+	sin45 := math.Sqrt(0.5)
+	into45 := New(sin45, 0, sin45)
+	glassSin := sin45 / 1.5
+	exit30 := New(0.5, 0, math.Sqrt(0.75))
+
+	tests := []struct {
+		name      string
+		v         Vector
+		normal    Vector
+		eta       float64
+		refracts  bool
+		refracted Vector
+	}{
+		{
+			name:      "normal incidence passes straight through",
+			v:         *New(0, 0, 1),
+			normal:    *New(0, 0, -1),
+			eta:       1 / 1.5,
+			refracts:  true,
+			refracted: *New(0, 0, 1),
+		},
+		{
+			name:      "45 degrees from air into glass bends towards the normal",
+			v:         *into45,
+			normal:    *New(0, 0, -1),
+			eta:       1 / 1.5,
+			refracts:  true,
+			refracted: *New(glassSin, 0, math.Sqrt(1-glassSin*glassSin)),
+		},
+		{
+			name:      "30 degrees from glass into air bends away from the normal",
+			v:         *exit30,
+			normal:    *New(0, 0, -1),
+			eta:       1.5,
+			refracts:  true,
+			refracted: *New(0.75, 0, math.Sqrt(1-0.75*0.75)),
+		},
+		{
+			name:     "45 degrees from glass into air is totally internally reflected",
+			v:        *into45,
+			normal:   *New(0, 0, -1),
+			eta:      1.5,
+			refracts: false,
+		},
+		{
+			name:      "same medium doesn't bend",
+			v:         *into45,
+			normal:    *New(0, 0, -1),
+			eta:       1,
+			refracts:  true,
+			refracted: *into45,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := test.v.Refract(test.normal, test.eta)
+			if ok != test.refracts {
+				t.Fatalf("Refract() refracts = %t, want %t", ok, test.refracts)
+			}
+			if ok && !VectorIsEqual(&got, &test.refracted) {
+				t.Errorf("Refract() = %v, want %v", got, test.refracted)
+			}
+		})
+	}
+}
+
+func TestVector_OrthonormalBasis(t *testing.T) {
+	vectors := []Vector{*New(0, 0, 1), *New(1, 0, 0), *New(0, -1, 0), New(1, 2, -3).Normalize(), New(-0.95, 0.1, 0.3).Normalize()}
+	for i, v := range vectors {
+		tangent, bitangent := v.OrthonormalBasis()
+		for _, pair := range [][2]Vector{{v, tangent}, {v, bitangent}, {tangent, bitangent}} {
+			if dot := pair[0].Dot(pair[1]); math.Abs(dot) > 1e-12 {
+				t.Errorf("test %d: expected %v and %v to be perpendicular, got a dot product of %g", i, pair[0], pair[1], dot)
+			}
+		}
+		for _, w := range []Vector{tangent, bitangent} {
+			if length := math.Sqrt(w.Dot(w)); math.Abs(length-1) > 1e-12 {
+				t.Errorf("test %d: expected %v to be unit length, got %g", i, w, length)
+			}
+		}
+	}
+}
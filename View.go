@@ -0,0 +1,14 @@
+//go:build !headless
+
+package main
+
+import (
+	"goRay/Camera"
+	"goRay/Renderer"
+)
+
+// openViewer shows the camera in the SDL window until it is closed
+func openViewer(camera *Camera.Camera) error {
+	Renderer.Render(int32(camera.Width()), int32(camera.Height()), *camera)
+	return nil
+}
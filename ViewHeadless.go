@@ -0,0 +1,13 @@
+//go:build headless
+
+package main
+
+import (
+	"errors"
+	"goRay/Camera"
+)
+
+// openViewer fails in headless builds, they leave out SDL so they build without cgo
+func openViewer(camera *Camera.Camera) error {
+	return errors.New("this goRay was built with -tags headless and has no viewer, use goRay render")
+}